@@ -34,6 +34,7 @@ import (
 type buildOptions struct {
 	*projectOptions
 	composeOptions
+	timingsOptions
 	quiet    bool
 	pull     bool
 	progress string
@@ -71,7 +72,7 @@ func buildCommand(p *projectOptions, backend api.Service) *cobra.Command {
 			if !utils.StringContains(printerModes, opts.progress) {
 				return fmt.Errorf("unsupported --progress value %q", opts.progress)
 			}
-			return nil
+			return opts.validateTimings()
 		}),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
 			return runBuild(ctx, backend, opts, args)
@@ -93,6 +94,7 @@ func buildCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	cmd.Flags().MarkHidden("no-rm") //nolint:errcheck
 	cmd.Flags().StringVarP(&opts.memory, "memory", "m", "", "Set memory limit for the build container. Not supported on buildkit yet.")
 	cmd.Flags().MarkHidden("memory") //nolint:errcheck
+	opts.addTimingsFlag(cmd.Flags())
 
 	return cmd
 }
@@ -103,12 +105,14 @@ func runBuild(ctx context.Context, backend api.Service, opts buildOptions, servi
 		return err
 	}
 
-	return backend.Build(ctx, project, api.BuildOptions{
-		Pull:     opts.pull,
-		Progress: opts.progress,
-		Args:     types.NewMappingWithEquals(opts.args),
-		NoCache:  opts.noCache,
-		Quiet:    opts.quiet,
-		Services: services,
+	return opts.withTimings(ctx, project, func(ctx context.Context) error {
+		return backend.Build(ctx, project, api.BuildOptions{
+			Pull:     opts.pull,
+			Progress: opts.progress,
+			Args:     types.NewMappingWithEquals(opts.args),
+			NoCache:  opts.noCache,
+			Quiet:    opts.quiet,
+			Services: services,
+		})
 	})
 }
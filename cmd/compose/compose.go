@@ -166,6 +166,19 @@ func (o *projectOptions) toProjectName() (string, error) {
 }
 
 func (o *projectOptions) toProject(services []string, po ...cli.ProjectOptionsFn) (*types.Project, error) {
+	project, err := o.toUnprunedProject(services, po...)
+	if err != nil {
+		return nil, err
+	}
+
+	project.WithoutUnnecessaryResources()
+
+	err = project.ForServices(services)
+	return project, err
+}
+
+// toUnprunedProject loads the project with profiles applied, keeping the resources no service uses
+func (o *projectOptions) toUnprunedProject(services []string, po ...cli.ProjectOptionsFn) (*types.Project, error) {
 	options, err := o.toProjectOptions(po...)
 	if err != nil {
 		return nil, compose.WrapComposeError(err)
@@ -193,11 +206,7 @@ func (o *projectOptions) toProject(services []string, po ...cli.ProjectOptionsFn
 	}
 
 	project.ApplyProfiles(o.Profiles)
-
-	project.WithoutUnnecessaryResources()
-
-	err = project.ForServices(services)
-	return project, err
+	return project, nil
 }
 
 func (o *projectOptions) toProjectOptions(po ...cli.ProjectOptionsFn) (*cli.ProjectOptions, error) {
@@ -306,6 +315,11 @@ func RootCommand(backend api.Service) *cobra.Command {
 		pullCommand(&opts, backend),
 		createCommand(&opts, backend),
 		copyCommand(&opts, backend),
+		volumesCommand(&opts, backend),
+		networksCommand(&opts, backend),
+		superviseCommand(&opts, backend),
+		lintCommand(&opts, backend),
+		secretsCommand(),
 	)
 	command.Flags().SetInterspersed(false)
 	opts.addProjectFlags(command.Flags())
@@ -28,17 +28,18 @@ import (
 )
 
 type createOptions struct {
-	Build         bool
-	noBuild       bool
-	removeOrphans bool
-	ignoreOrphans bool
-	forceRecreate bool
-	noRecreate    bool
-	recreateDeps  bool
-	noInherit     bool
-	timeChanged   bool
-	timeout       int
-	quietPull     bool
+	Build            bool
+	noBuild          bool
+	removeOrphans    bool
+	ignoreOrphans    bool
+	forceRecreate    bool
+	noRecreate       bool
+	recreateDeps     bool
+	noInherit        bool
+	timeChanged      bool
+	timeout          int
+	quietPull        bool
+	recreateNetworks bool
 }
 
 func createCommand(p *projectOptions, backend api.Service) *cobra.Command {
@@ -63,6 +64,7 @@ func createCommand(p *projectOptions, backend api.Service) *cobra.Command {
 				RecreateDependencies: opts.dependenciesRecreateStrategy(),
 				Inherit:              !opts.noInherit,
 				Timeout:              opts.GetTimeout(),
+				RecreateNetworks:     opts.recreateNetworks,
 				QuietPull:            false,
 			})
 		}),
@@ -73,6 +75,7 @@ func createCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	flags.BoolVar(&opts.noBuild, "no-build", false, "Don't build an image, even if it's missing.")
 	flags.BoolVar(&opts.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed.")
 	flags.BoolVar(&opts.noRecreate, "no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	flags.BoolVar(&opts.recreateNetworks, "recreate-networks", false, "Recreate networks which configuration changed, reconnecting containers.")
 	return cmd
 }
 
@@ -0,0 +1,524 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/buger/goterm"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli/streams"
+	"github.com/moby/term"
+	"github.com/morikuni/aec"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const (
+	dashboardMaxLogLines = 2000
+	dashboardRefresh     = time.Second
+	dashboardRender      = 250 * time.Millisecond
+)
+
+type dashboardLog struct {
+	service   string
+	container string
+	line      string
+}
+
+// dashboard is a full-screen view of the project services for attached `up`, fed by the container events collected
+// as a LogConsumer and refreshed using the compose API. It also collects progress events of the actions it runs.
+type dashboard struct {
+	ctx     context.Context
+	backend api.Service
+	project *types.Project
+	in      *streams.In
+	out     io.Writer
+
+	mutex      sync.Mutex
+	services   []string
+	selected   int // 0 selects all services, i selects services[i-1]
+	containers []api.ContainerSummary
+	logs       []dashboardLog
+	owners     map[string]string
+	status     string
+
+	started   bool
+	closed    bool
+	suspended bool
+	state     *term.State
+	done      chan struct{}
+}
+
+func newDashboard(ctx context.Context, backend api.Service, project *types.Project) *dashboard {
+	services := project.ServiceNames()
+	sort.Strings(services)
+	return &dashboard{
+		ctx:      ctx,
+		backend:  backend,
+		project:  project,
+		in:       streams.NewIn(os.Stdin),
+		out:      os.Stdout,
+		services: services,
+		owners:   map[string]string{},
+		done:     make(chan struct{}),
+	}
+}
+
+// Register implements api.LogConsumer, the full-screen view starts when the first container gets attached
+func (d *dashboard) Register(container string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.started || d.closed {
+		return
+	}
+	d.started = true
+	if err := d.enter(); err != nil {
+		d.closed = true
+		return
+	}
+	go d.renderLoop()
+	go d.refreshLoop()
+	go d.readKeys()
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			d.Close()
+		case <-d.done:
+		}
+	}()
+}
+
+// Log implements api.LogConsumer
+func (d *dashboard) Log(container, service, message string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.owners[container] = service
+	for _, line := range strings.Split(message, "\n") {
+		d.appendLog(dashboardLog{service: service, container: container, line: line})
+	}
+}
+
+// Status implements api.LogConsumer
+func (d *dashboard) Status(container, msg string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	service, ok := d.owners[container]
+	if !ok {
+		// container names are `service-number` once the project prefix has been removed
+		if i := strings.LastIndex(container, compose.Separator); i > 0 {
+			service = container[:i]
+		}
+	}
+	d.appendLog(dashboardLog{service: service, container: container, line: msg})
+}
+
+func (d *dashboard) appendLog(l dashboardLog) {
+	d.logs = append(d.logs, l)
+	if len(d.logs) > dashboardMaxLogLines {
+		d.logs = d.logs[len(d.logs)-dashboardMaxLogLines:]
+	}
+}
+
+// Start implements progress.Writer
+func (d *dashboard) Start(context.Context) error {
+	return nil
+}
+
+// Stop implements progress.Writer
+func (d *dashboard) Stop() {}
+
+// Event implements progress.Writer, the last event is displayed as status
+func (d *dashboard) Event(e progress.Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	status := []string{e.ID}
+	for _, text := range []string{e.Text, e.StatusText} {
+		if text != "" {
+			status = append(status, text)
+		}
+	}
+	d.status = strings.Join(status, " ")
+}
+
+// Events implements progress.Writer
+func (d *dashboard) Events(events []progress.Event) {
+	for _, e := range events {
+		d.Event(e)
+	}
+}
+
+// TailMsgf implements progress.Writer
+func (d *dashboard) TailMsgf(msg string, args ...interface{}) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.status = fmt.Sprintf(msg, args...)
+}
+
+// Close leaves the full-screen view and restores the terminal
+func (d *dashboard) Close() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return
+	}
+	d.closed = true
+	if !d.started {
+		return
+	}
+	close(d.done)
+	if !d.suspended {
+		d.leave()
+	}
+}
+
+func (d *dashboard) enter() error {
+	state, err := term.SetRawTerminal(d.in.FD())
+	if err != nil {
+		return err
+	}
+	d.state = state
+	fmt.Fprint(d.out, "\x1b[?1049h"+aec.Hide.String()) //nolint:errcheck
+	return nil
+}
+
+func (d *dashboard) leave() {
+	fmt.Fprint(d.out, aec.Show.String()+"\x1b[?1049l") //nolint:errcheck
+	if d.state != nil {
+		term.RestoreTerminal(d.in.FD(), d.state) //nolint:errcheck
+	}
+}
+
+// suspend leaves the full-screen view to run an interactive action, then gets back to it
+func (d *dashboard) suspend(fn func() error) {
+	d.mutex.Lock()
+	if d.closed {
+		d.mutex.Unlock()
+		return
+	}
+	d.suspended = true
+	d.leave()
+	d.mutex.Unlock()
+
+	if err := fn(); err != nil {
+		fmt.Fprintln(d.out, err.Error())                              //nolint:errcheck
+		fmt.Fprint(d.out, "Press Enter to get back to the dashboard") //nolint:errcheck
+		fmt.Fscanln(d.in)                                             //nolint:errcheck
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.suspended = false
+	if d.closed {
+		return
+	}
+	if err := d.enter(); err != nil {
+		d.closed = true
+		close(d.done)
+	}
+}
+
+func (d *dashboard) renderLoop() {
+	ticker := time.NewTicker(dashboardRender)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+			d.mutex.Lock()
+			if !d.suspended && !d.closed {
+				fmt.Fprint(d.out, d.render(goterm.Width(), goterm.Height())) //nolint:errcheck
+			}
+			d.mutex.Unlock()
+		}
+	}
+}
+
+func (d *dashboard) refreshLoop() {
+	ticker := time.NewTicker(dashboardRefresh)
+	defer ticker.Stop()
+	for {
+		containers, err := d.backend.Ps(d.ctx, d.project.Name, api.PsOptions{})
+		d.mutex.Lock()
+		if err == nil {
+			d.containers = containers
+		}
+		d.mutex.Unlock()
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *dashboard) readKeys() {
+	buf := make([]byte, 3)
+	for {
+		n, err := d.in.Read(buf)
+		if err != nil {
+			return
+		}
+		select {
+		case <-d.done:
+			return
+		default:
+		}
+		d.handleKey(string(buf[:n]))
+	}
+}
+
+func (d *dashboard) handleKey(key string) {
+	switch key {
+	case "\x1b[A", "k":
+		d.move(-1)
+	case "\x1b[B", "j":
+		d.move(1)
+	case "r":
+		d.onSelectedService(func(ctx context.Context, service string) error {
+			return d.backend.Restart(ctx, d.project, api.RestartOptions{Services: []string{service}})
+		})
+	case "s":
+		d.onSelectedService(func(ctx context.Context, service string) error {
+			return d.backend.Stop(ctx, d.project, api.StopOptions{Services: []string{service}})
+		})
+	case "b":
+		if service := d.selectedService(); service != "" {
+			d.suspend(func() error {
+				return d.rebuild(service)
+			})
+		}
+	case "e":
+		if service := d.selectedService(); service != "" {
+			d.suspend(func() error {
+				return d.exec(service)
+			})
+		}
+	case "q", "\x03":
+		d.Close()
+		// let `up` gracefully stop the application, as it does on Ctrl+C
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(os.Interrupt) //nolint:errcheck
+		}
+	}
+}
+
+func (d *dashboard) move(delta int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.selected = (d.selected + delta + len(d.services) + 1) % (len(d.services) + 1)
+}
+
+func (d *dashboard) selectedService() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.selected == 0 {
+		d.status = "Select a service first"
+		return ""
+	}
+	return d.services[d.selected-1]
+}
+
+// onSelectedService runs fn in background, reporting progress in the status line
+func (d *dashboard) onSelectedService(fn func(ctx context.Context, service string) error) {
+	service := d.selectedService()
+	if service == "" {
+		return
+	}
+	go func() {
+		ctx := progress.WithContextWriter(d.ctx, d)
+		if err := fn(ctx, service); err != nil {
+			d.TailMsgf("%s: %s", service, err.Error())
+		}
+	}()
+}
+
+func (d *dashboard) rebuild(service string) error {
+	err := d.backend.Build(d.ctx, d.project, api.BuildOptions{Services: []string{service}})
+	if err != nil {
+		return err
+	}
+	err = d.backend.Create(d.ctx, d.project, api.CreateOptions{
+		Services:             []string{service},
+		Recreate:             api.RecreateForce,
+		RecreateDependencies: api.RecreateNever,
+		Inherit:              true,
+	})
+	if err != nil {
+		return err
+	}
+	return d.backend.Start(d.ctx, d.project, api.StartOptions{})
+}
+
+func (d *dashboard) exec(service string) error {
+	container, ok := d.execTarget(service)
+	if !ok {
+		return fmt.Errorf("service %q has no running container", service)
+	}
+	fmt.Fprintf(d.out, "Running a shell in service %q, exit it to get back to the dashboard\n", service) //nolint:errcheck
+	_, err := d.backend.Exec(d.ctx, d.project.Name, api.RunOptions{
+		Service:     service,
+		ContainerID: container,
+		Command:     []string{"sh"},
+		Tty:         d.in.IsTerminal(),
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	})
+	return err
+}
+
+// execTarget returns the ID of the running service container with the lowest number, one-off containers not being
+// listed by the dashboard
+func (d *dashboard) execTarget(service string) (string, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	var (
+		target string
+		lowest int
+	)
+	for _, c := range d.containers {
+		if c.Service != service || c.State != "running" {
+			continue
+		}
+		number, err := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+		if err != nil {
+			continue
+		}
+		if target == "" || number < lowest {
+			target, lowest = c.ID, number
+		}
+	}
+	return target, target != ""
+}
+
+// render builds the full-screen view for a terminal of the given size
+func (d *dashboard) render(width, height int) string {
+	var lines []string
+	header := fmt.Sprintf(" %s   ↑/↓ select  r restart  s stop  b rebuild  e exec  q quit", d.project.Name)
+	lines = append(lines, aec.Bold.Apply(fit(header, width)))
+
+	nameWidth := len("(all)")
+	for _, s := range d.services {
+		if len(s) > nameWidth {
+			nameWidth = len(s)
+		}
+	}
+	row := func(selected bool, cols ...string) string {
+		cursor := "  "
+		if selected {
+			cursor = "> "
+		}
+		l := fmt.Sprintf("%s%-*s  %-12s  %-10s  %-8s  %s", cursor, nameWidth, cols[0], cols[1], cols[2], cols[3], cols[4])
+		l = fit(l, width)
+		if selected {
+			l = aec.Inverse.Apply(l)
+		}
+		return l
+	}
+	lines = append(lines, fit(row(false, "SERVICE", "STATE", "HEALTH", "RESTARTS", "PORTS"), width))
+	lines = append(lines, row(d.selected == 0, "(all)", "", "", "", ""))
+	for i, service := range d.services {
+		state, health, restarts, ports := d.serviceSummary(service)
+		lines = append(lines, row(d.selected == i+1, service, state, health, restarts, ports))
+	}
+	lines = append(lines, strings.Repeat("─", width))
+
+	logHeight := height - len(lines) - 1
+	var logs []string
+	for i := len(d.logs) - 1; i >= 0 && len(logs) < logHeight; i-- {
+		l := d.logs[i]
+		if d.selected > 0 && l.service != d.services[d.selected-1] {
+			continue
+		}
+		logs = append([]string{fit(fmt.Sprintf("%s | %s", l.container, l.line), width)}, logs...)
+	}
+	for len(logs) < logHeight {
+		logs = append(logs, "")
+	}
+	lines = append(lines, logs...)
+	lines = append(lines, aec.Faint.Apply(fit(d.status, width)))
+
+	return aec.Position(1, 1).String() + strings.Join(lines, aec.EraseLine(aec.EraseModes.Tail).String()+"\r\n") + aec.EraseDisplay(aec.EraseModes.Tail).String()
+}
+
+// serviceSummary aggregates the state of the service containers
+func (d *dashboard) serviceSummary(service string) (state, health, restarts, ports string) {
+	var (
+		running, total, restartCount int
+		states, healths, published   []string
+	)
+	for _, c := range d.containers {
+		if c.Service != service {
+			continue
+		}
+		total++
+		if c.State == "running" {
+			running++
+		}
+		states = appendIfMissing(states, c.State)
+		if c.Health != "" {
+			healths = appendIfMissing(healths, c.Health)
+		}
+		restartCount += c.RestartCount
+		if p := DisplayablePorts(c); p != "" {
+			published = appendIfMissing(published, p)
+		}
+	}
+	if total == 0 {
+		return "-", "", "", ""
+	}
+	state = strings.Join(states, ",")
+	if total > 1 {
+		state = fmt.Sprintf("%s %d/%d", state, running, total)
+	}
+	return state, strings.Join(healths, ","), strconv.Itoa(restartCount), strings.Join(published, ", ")
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// fit truncates s to the terminal width
+func fit(s string, width int) string {
+	r := []rune(s)
+	if width > 0 && len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
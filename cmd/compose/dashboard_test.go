@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+func testDashboard() *dashboard {
+	d := newDashboard(context.Background(), nil, &types.Project{
+		Name:     "myproject",
+		Services: types.Services{{Name: "web"}, {Name: "db"}},
+	})
+	d.containers = []api.ContainerSummary{
+		{Service: "db", State: "running", Health: "healthy"},
+		{Service: "web", State: "running", RestartCount: 1, Publishers: api.PortPublishers{
+			{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 8080, Protocol: "tcp"},
+		}},
+		{Service: "web", State: "exited", RestartCount: 2},
+	}
+	d.Log("db-1", "db", "ready to accept connections")
+	d.Log("web-1", "web", "listening on :80")
+	d.Status("web-2", "exited with code 1")
+	return d
+}
+
+func TestDashboardServiceSummary(t *testing.T) {
+	d := testDashboard()
+	state, health, restarts, ports := d.serviceSummary("web")
+	assert.Equal(t, state, "running,exited 1/2")
+	assert.Equal(t, health, "")
+	assert.Equal(t, restarts, "3")
+	assert.Equal(t, ports, "0.0.0.0:8080->80/tcp")
+
+	state, health, _, _ = d.serviceSummary("db")
+	assert.Equal(t, state, "running")
+	assert.Equal(t, health, "healthy")
+}
+
+func TestDashboardExecTarget(t *testing.T) {
+	d := testDashboard()
+	d.containers = []api.ContainerSummary{
+		{ID: "web2", Service: "web", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "2"}},
+		{ID: "web1", Service: "web", State: "exited", Labels: map[string]string{api.ContainerNumberLabel: "1"}},
+		{ID: "web3", Service: "web", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "3"}},
+	}
+	target, ok := d.execTarget("web")
+	assert.Assert(t, ok)
+	assert.Equal(t, target, "web2")
+
+	_, ok = d.execTarget("db")
+	assert.Assert(t, !ok)
+}
+
+func TestDashboardRender(t *testing.T) {
+	d := testDashboard()
+	screen := d.render(80, 12)
+	assert.Assert(t, strings.Contains(screen, "myproject"))
+	assert.Assert(t, strings.Contains(screen, "db-1 | ready to accept connections"))
+	assert.Assert(t, strings.Contains(screen, "web-1 | listening on :80"))
+	assert.Equal(t, strings.Count(screen, "\r\n"), 11)
+
+	// services are sorted, select "web" to filter logs
+	d.handleKey("j")
+	d.handleKey("\x1b[B")
+	assert.Equal(t, d.selectedService(), "web")
+	screen = d.render(80, 12)
+	assert.Assert(t, !strings.Contains(screen, "ready to accept connections"))
+	assert.Assert(t, strings.Contains(screen, "web-2 | exited with code 1"))
+
+	d.handleKey("k")
+	d.handleKey("k")
+	assert.Equal(t, d.selectedService(), "")
+
+	d.Event(progress.NewEvent("Container web-1", progress.Done, "Restarted"))
+	assert.Equal(t, d.status, "Container web-1 Restarted")
+}
@@ -0,0 +1,268 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dockercli "github.com/docker/cli/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/internal"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+)
+
+const (
+	lintText  = "text"
+	lintJSON  = "json"
+	lintSARIF = "sarif"
+)
+
+type lintOptions struct {
+	*projectOptions
+	format string
+	rules  []string
+}
+
+func lintCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	opts := lintOptions{
+		projectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "lint [SERVICE...]",
+		Short: "Check the compose model for risky or invalid configurations",
+		PreRunE: Adapt(func(ctx context.Context, args []string) error {
+			switch opts.format {
+			case lintText, lintJSON, lintSARIF:
+				return nil
+			}
+			return fmt.Errorf("unsupported format %q", opts.format)
+		}),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runLint(ctx, os.Stdout, backend, opts, args)
+		}),
+		ValidArgsFunction: serviceCompletion(p),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", lintText, "Format the output. Values: [text | json | sarif]")
+	flags.StringArrayVar(&opts.rules, "rule", nil, "Set the level of a rule, as RULE=LEVEL with LEVEL one of [error | warning | note | off]")
+	return cmd
+}
+
+func runLint(ctx context.Context, out io.Writer, backend api.Service, opts lintOptions, services []string) error {
+	levels := map[string]string{}
+	for _, rule := range opts.rules {
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid --rule %q, expected RULE=LEVEL", rule)
+		}
+		levels[parts[0]] = parts[1]
+	}
+	// unused resources are kept to be reported
+	project, err := opts.toUnprunedProject(services)
+	if err != nil {
+		return err
+	}
+	if err := project.ForServices(services); err != nil {
+		return err
+	}
+	findings, err := backend.Lint(ctx, project, api.LintOptions{Levels: levels})
+	if err != nil {
+		return err
+	}
+	if err := printLint(out, opts.format, findings); err != nil {
+		return err
+	}
+	errs := 0
+	for _, finding := range findings {
+		if finding.Level == api.LintLevelError {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return dockercli.StatusError{
+			StatusCode: 1,
+			Status:     fmt.Sprintf("%d lint error(s) found", errs),
+		}
+	}
+	return nil
+}
+
+func printLint(out io.Writer, format string, findings []api.LintFinding) error {
+	switch format {
+	case lintJSON:
+		return json.NewEncoder(out).Encode(toLintJSON(findings))
+	case lintSARIF:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(toSARIF(findings))
+	}
+	for _, finding := range findings {
+		position := ""
+		if finding.File != "" {
+			position = fmt.Sprintf("%s:%d:%d: ", relativeLintPath(finding.File), finding.Line, finding.Column)
+		}
+		if _, err := fmt.Fprintf(out, "%s%s: %s [%s]\n", position, finding.Level, finding.Message, finding.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relativeLintPath reports compose files relative to the current directory when they're below it
+func relativeLintPath(file string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return file
+	}
+	rel, err := filepath.Rel(wd, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return rel
+}
+
+type lintFindingJSON struct {
+	Rule    string `json:"rule"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+func toLintJSON(findings []api.LintFinding) []lintFindingJSON {
+	result := []lintFindingJSON{}
+	for _, finding := range findings {
+		result = append(result, lintFindingJSON{
+			Rule:    finding.Rule,
+			Level:   finding.Level,
+			Message: finding.Message,
+			File:    finding.File,
+			Line:    finding.Line,
+			Column:  finding.Column,
+		})
+	}
+	return result
+}
+
+// sarifLog is the subset of the SARIF 2.1.0 format used to report lint findings to code scanning tools
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func toSARIF(findings []api.LintFinding) sarifLog {
+	driver := sarifDriver{
+		Name:           "docker-compose",
+		Version:        internal.Version,
+		InformationURI: "https://github.com/docker/compose",
+	}
+	for _, rule := range compose.LintRules() {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Level},
+		})
+	}
+	results := []sarifResult{}
+	for _, finding := range findings {
+		result := sarifResult{
+			RuleID:  finding.Rule,
+			Level:   finding.Level,
+			Message: sarifMessage{Text: finding.Message},
+		}
+		if finding.File != "" {
+			location := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(relativeLintPath(finding.File))},
+			}
+			if finding.Line > 0 {
+				location.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		results = append(results, result)
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func lintFindings(t *testing.T) []api.LintFinding {
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	return []api.LintFinding{
+		{Rule: "privileged", Level: api.LintLevelWarning, Message: `service "web" runs privileged containers`,
+			File: filepath.Join(wd, "compose.yaml"), Line: 4, Column: 5},
+		{Rule: "dependency-cycle", Level: api.LintLevelError, Message: "cycle found: a -> b -> a"},
+	}
+}
+
+func TestPrintLintText(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printLint(&out, lintText, lintFindings(t)))
+	assert.Equal(t, out.String(), `compose.yaml:4:5: warning: service "web" runs privileged containers [privileged]
+error: cycle found: a -> b -> a [dependency-cycle]
+`)
+}
+
+func TestPrintLintSARIF(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printLint(&out, lintSARIF, lintFindings(t)))
+	var log sarifLog
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &log))
+	assert.Equal(t, log.Version, "2.1.0")
+	assert.Equal(t, len(log.Runs), 1)
+	assert.Equal(t, log.Runs[0].Tool.Driver.Name, "docker-compose")
+	assert.Assert(t, len(log.Runs[0].Tool.Driver.Rules) > 0)
+	assert.DeepEqual(t, log.Runs[0].Results, []sarifResult{
+		{
+			RuleID:  "privileged",
+			Level:   "warning",
+			Message: sarifMessage{Text: `service "web" runs privileged containers`},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "compose.yaml"},
+				Region:           &sarifRegion{StartLine: 4, StartColumn: 5},
+			}}},
+		},
+		{
+			RuleID:  "dependency-cycle",
+			Level:   "error",
+			Message: sarifMessage{Text: "cycle found: a -> b -> a"},
+		},
+	})
+}
+
+func TestPrintLintJSONWithoutFindings(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printLint(&out, lintJSON, nil))
+	assert.Equal(t, out.String(), "[]\n")
+}
@@ -57,7 +57,8 @@ func listCommand(backend api.Service) *cobra.Command {
 }
 
 var acceptedListFilters = map[string]bool{
-	"name": true,
+	"name":  true,
+	"label": true,
 }
 
 func runList(ctx context.Context, backend api.Service, opts lsOptions) error {
@@ -67,21 +68,18 @@ func runList(ctx context.Context, backend api.Service, opts lsOptions) error {
 		return err
 	}
 
-	stackList, err := backend.List(ctx, api.ListOptions{All: opts.All})
+	stackList, err := backend.List(ctx, api.ListOptions{
+		All:    opts.All,
+		Labels: filters.Get("label"),
+	})
 	if err != nil {
 		return err
 	}
-	if opts.Quiet {
-		for _, s := range stackList {
-			fmt.Println(s.Name)
-		}
-		return nil
-	}
 
-	if filters.Len() > 0 {
+	if filters.Contains("name") {
 		var filtered []api.Stack
 		for _, s := range stackList {
-			if filters.Contains("name") && !filters.Match("name", s.Name) {
+			if !filters.Match("name", s.Name) {
 				continue
 			}
 			filtered = append(filtered, s)
@@ -89,25 +87,54 @@ func runList(ctx context.Context, backend api.Service, opts lsOptions) error {
 		stackList = filtered
 	}
 
+	if opts.Quiet {
+		for _, s := range stackList {
+			fmt.Println(s.Name)
+		}
+		return nil
+	}
+
 	view := viewFromStackList(stackList)
 	return formatter.Print(view, opts.Format, os.Stdout, func(w io.Writer) {
 		for _, stack := range view {
-			_, _ = fmt.Fprintf(w, "%s\t%s\n", stack.Name, stack.Status)
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", stack.Name, stack.Status, stack.ConfigFiles)
 		}
-	}, "NAME", "STATUS")
+	}, "NAME", "STATUS", "CONFIG FILES")
 }
 
 type stackView struct {
-	Name   string
-	Status string
+	Name        string
+	Status      string
+	ConfigFiles string
+	WorkingDir  string
+	Version     string
+	Services    []stackServiceView
+}
+
+type stackServiceView struct {
+	Name    string
+	Running int
+	Desired int
 }
 
 func viewFromStackList(stackList []api.Stack) []stackView {
 	retList := make([]stackView, len(stackList))
 	for i, s := range stackList {
+		services := make([]stackServiceView, len(s.Services))
+		for j, service := range s.Services {
+			services[j] = stackServiceView{
+				Name:    service.Name,
+				Running: service.Replicas,
+				Desired: service.Desired,
+			}
+		}
 		retList[i] = stackView{
-			Name:   s.Name,
-			Status: strings.TrimSpace(fmt.Sprintf("%s %s", s.Status, s.Reason)),
+			Name:        s.Name,
+			Status:      strings.TrimSpace(fmt.Sprintf("%s %s", s.Status, s.Reason)),
+			ConfigFiles: s.ConfigFiles,
+			WorkingDir:  s.WorkingDir,
+			Version:     s.Version,
+			Services:    services,
 		}
 	}
 	return retList
@@ -22,6 +22,7 @@ import (
 
 	"github.com/docker/compose/v2/cmd/formatter"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose/v2/pkg/api"
@@ -37,6 +38,10 @@ type logsOptions struct {
 	noColor    bool
 	noPrefix   bool
 	timestamps bool
+	grep       string
+	level      string
+	format     string
+	ordered    bool
 }
 
 func logsCommand(p *projectOptions, backend api.Service) *cobra.Command {
@@ -59,6 +64,10 @@ func logsCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	flags.BoolVar(&opts.noPrefix, "no-log-prefix", false, "Don't print prefix in logs.")
 	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps.")
 	flags.StringVar(&opts.tail, "tail", "all", "Number of lines to show from the end of the logs for each container.")
+	flags.StringVar(&opts.grep, "grep", "", "Only show log lines matching a regular expression.")
+	flags.StringVar(&opts.level, "level", "", "Only show JSON log lines with this level or higher. Values: [trace | debug | info | warn | error | fatal]")
+	flags.StringVar(&opts.format, "format", "pretty", "Format the output. Values: [pretty | json]")
+	flags.BoolVar(&opts.ordered, "ordered", false, "Merge logs from all containers in timestamp order.")
 	return logsCmd
 }
 
@@ -67,7 +76,15 @@ func runLogs(ctx context.Context, backend api.Service, opts logsOptions, service
 	if err != nil {
 		return err
 	}
-	consumer := formatter.NewLogConsumer(ctx, os.Stdout, !opts.noColor, !opts.noPrefix)
+	var consumer api.LogConsumer
+	switch opts.format {
+	case formatter.PRETTY, "":
+		consumer = formatter.NewLogConsumer(ctx, os.Stdout, !opts.noColor, !opts.noPrefix)
+	case formatter.JSON:
+		consumer = formatter.NewJSONLogConsumer(ctx, os.Stdout)
+	default:
+		return errors.Wrapf(api.ErrParsingFailed, "format value %q could not be parsed", opts.format)
+	}
 	return backend.Logs(ctx, projectName, consumer, api.LogOptions{
 		Services:   services,
 		Follow:     opts.follow,
@@ -75,5 +92,8 @@ func runLogs(ctx context.Context, backend api.Service, opts logsOptions, service
 		Since:      opts.since,
 		Until:      opts.until,
 		Timestamps: opts.timestamps,
+		Grep:       opts.grep,
+		Level:      opts.level,
+		Ordered:    opts.ordered,
 	})
 }
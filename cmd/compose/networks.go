@@ -0,0 +1,38 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func networksCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "networks",
+		Short: "Manage project networks",
+	}
+	cmd.AddCommand(
+		resourcesListCommand(p, "List project networks", func(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error) {
+			return backend.NetworksList(ctx, projectName, options)
+		}),
+	)
+	return cmd
+}
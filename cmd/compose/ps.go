@@ -24,11 +24,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/compose/v2/cmd/formatter"
 	"github.com/docker/compose/v2/pkg/utils"
 
+	"github.com/compose-spec/compose-go/types"
 	formatter2 "github.com/docker/cli/cli/command/formatter"
+	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
@@ -41,25 +44,54 @@ type psOptions struct {
 	All      bool
 	Quiet    bool
 	Services bool
-	Filter   string
+	NoTrunc  bool
+	Verbose  bool
+	Filter   []string
 	Status   []string
+	filters  psFilters
+}
+
+// psFilters hold the criteria set by `--filter` a container must match to be listed
+type psFilters struct {
+	services  []string
+	health    []string
+	exitCodes []int
+	labels    []string
+	source    string
+	// sourceServices are the services matching source, resolved from the compose model
+	sourceServices []string
 }
 
 func (p *psOptions) parseFilter() error {
-	if p.Filter == "" {
-		return nil
-	}
-	parts := strings.SplitN(p.Filter, "=", 2)
-	if len(parts) != 2 {
-		return errors.New("arguments to --filter should be in form KEY=VAL")
-	}
-	switch parts[0] {
-	case "status":
-		p.Status = append(p.Status, parts[1])
-	case "source":
-		return api.ErrNotImplemented
-	default:
-		return fmt.Errorf("unknown filter %s", parts[0])
+	for _, filter := range p.Filter {
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 {
+			return errors.New("arguments to --filter should be in form KEY=VAL")
+		}
+		key, value := parts[0], parts[1]
+		switch key {
+		case "status":
+			p.Status = append(p.Status, value)
+		case "service":
+			p.filters.services = append(p.filters.services, value)
+		case "health":
+			p.filters.health = append(p.filters.health, value)
+		case "exited":
+			code, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid exit code %q for filter exited", value)
+			}
+			p.filters.exitCodes = append(p.filters.exitCodes, code)
+		case "label":
+			p.filters.labels = append(p.filters.labels, value)
+		case "source":
+			if value != "image" && value != "build" {
+				return fmt.Errorf("invalid value %q for filter source, expected image or build", value)
+			}
+			p.filters.source = value
+		default:
+			return fmt.Errorf("unknown filter %s", key)
+		}
 	}
 	return nil
 }
@@ -80,13 +112,14 @@ func psCommand(p *projectOptions, backend api.Service) *cobra.Command {
 		ValidArgsFunction: serviceCompletion(p),
 	}
 	flags := psCmd.Flags()
-	flags.StringVar(&opts.Format, "format", "pretty", "Format the output. Values: [pretty | json]")
-	flags.StringVar(&opts.Filter, "filter", "", "Filter services by a property. Deprecated, use --status instead")
+	flags.StringVar(&opts.Format, "format", "pretty", "Format the output. Values: [pretty | json | TEMPLATE]")
+	flags.StringArrayVar(&opts.Filter, "filter", []string{}, "Filter services by a property. Values: [status | service | health | exited | label | source]=VALUE")
 	flags.StringArrayVar(&opts.Status, "status", []string{}, "Filter services by status. Values: [paused | restarting | removing | running | dead | created | exited]")
 	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
 	flags.BoolVar(&opts.Services, "services", false, "Display services")
 	flags.BoolVarP(&opts.All, "all", "a", false, "Show all stopped containers (including those created by the run command)")
-	flags.Lookup("filter").Hidden = true
+	flags.BoolVar(&opts.NoTrunc, "no-trunc", false, "Don't truncate output")
+	flags.BoolVar(&opts.Verbose, "verbose", false, "Display restart count, start and finish times, OOM kills and healthcheck log")
 	return psCmd
 }
 
@@ -117,6 +150,15 @@ SERVICES:
 		containers = filterByStatus(containers, opts.Status)
 	}
 
+	if opts.filters.source != "" {
+		project, err := opts.toProject(nil)
+		if err != nil {
+			return err
+		}
+		opts.filters.sourceServices = servicesBySource(project, opts.filters.source)
+	}
+	containers = opts.filters.apply(containers)
+
 	sort.Slice(containers, func(i, j int) bool {
 		return containers[i].Name < containers[j].Name
 	})
@@ -139,22 +181,75 @@ SERVICES:
 		return nil
 	}
 
+	if opts.Verbose && (opts.Format == formatter.PRETTY || opts.Format == "") {
+		err := formatter.Print(containers, opts.Format, os.Stdout,
+			verboseWritter(containers, opts.NoTrunc),
+			"NAME", "COMMAND", "SERVICE", "STATUS", "PORTS", "RESTARTS", "STARTED", "FINISHED", "OOMKILLED")
+		if err != nil {
+			return err
+		}
+		printHealthLogs(os.Stdout, containers)
+		return nil
+	}
+
 	return formatter.Print(containers, opts.Format, os.Stdout,
-		writter(containers),
+		writter(containers, opts.NoTrunc),
 		"NAME", "COMMAND", "SERVICE", "STATUS", "PORTS")
 }
 
-func writter(containers []api.ContainerSummary) func(w io.Writer) {
+func verboseWritter(containers []api.ContainerSummary, noTrunc bool) func(w io.Writer) {
+	return func(w io.Writer) {
+		for _, container := range containers {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%t\n", container.Name, strconv.Quote(displayableCommand(container, noTrunc)),
+				container.Service, displayableStatus(container), DisplayablePorts(container),
+				container.RestartCount, displayableTime(container.StartedAt), displayableTime(container.FinishedAt), container.OOMKilled)
+		}
+	}
+}
+
+// printHealthLogs prints the last healthcheck probes of containers with a healthcheck
+func printHealthLogs(out io.Writer, containers []api.ContainerSummary) {
+	for _, container := range containers {
+		if len(container.HealthLog) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "\n%s healthcheck log:\n", container.Name)
+		for _, l := range container.HealthLog {
+			_, _ = fmt.Fprintf(out, "  %s exit=%d %s\n", l.End.Local().Format(time.RFC3339), l.ExitCode, strings.TrimSpace(l.Output))
+		}
+	}
+}
+
+func displayableTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return units.HumanDuration(time.Since(t)) + " ago"
+}
+
+func displayableStatus(container api.ContainerSummary) string {
+	status := container.State
+	if status == "running" && container.Health != "" {
+		status = fmt.Sprintf("%s (%s)", container.State, container.Health)
+	} else if status == "exited" || status == "dead" {
+		status = fmt.Sprintf("%s (%d)", container.State, container.ExitCode)
+	}
+	return status
+}
+
+func displayableCommand(container api.ContainerSummary, noTrunc bool) string {
+	if noTrunc {
+		return container.Command
+	}
+	return formatter2.Ellipsis(container.Command, 20)
+}
+
+func writter(containers []api.ContainerSummary, noTrunc bool) func(w io.Writer) {
 	return func(w io.Writer) {
 		for _, container := range containers {
 			ports := DisplayablePorts(container)
-			status := container.State
-			if status == "running" && container.Health != "" {
-				status = fmt.Sprintf("%s (%s)", container.State, container.Health)
-			} else if status == "exited" || status == "dead" {
-				status = fmt.Sprintf("%s (%d)", container.State, container.ExitCode)
-			}
-			command := formatter2.Ellipsis(container.Command, 20)
+			status := displayableStatus(container)
+			command := displayableCommand(container, noTrunc)
 			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", container.Name, strconv.Quote(command), container.Service, status, ports)
 		}
 	}
@@ -179,6 +274,62 @@ func hasStatus(c api.ContainerSummary, statuses []string) bool {
 	return false
 }
 
+// servicesBySource selects the services which image is either pulled (`image`) or built (`build`)
+func servicesBySource(project *types.Project, source string) []string {
+	var services []string
+	for _, s := range project.Services {
+		if (s.Build != nil) == (source == "build") {
+			services = append(services, s.Name)
+		}
+	}
+	return services
+}
+
+func (f psFilters) apply(containers []api.ContainerSummary) []api.ContainerSummary {
+	// never nil, so that JSON output is an empty list
+	filtered := []api.ContainerSummary{}
+	for _, c := range containers {
+		if f.match(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func (f psFilters) match(c api.ContainerSummary) bool {
+	if len(f.services) > 0 && !utils.StringContains(f.services, c.Service) {
+		return false
+	}
+	if f.source != "" && !utils.StringContains(f.sourceServices, c.Service) {
+		return false
+	}
+	if len(f.health) > 0 && !utils.StringContains(f.health, c.Health) {
+		return false
+	}
+	if len(f.exitCodes) > 0 {
+		if c.State != "exited" && c.State != "dead" {
+			return false
+		}
+		found := false
+		for _, code := range f.exitCodes {
+			if c.ExitCode == code {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, label := range f.labels {
+		parts := strings.SplitN(label, "=", 2)
+		value, ok := c.Labels[parts[0]]
+		if !ok || len(parts) == 2 && value != parts[1] {
+			return false
+		}
+	}
+	return true
+}
+
 type portRange struct {
 	pStart   int
 	pEnd     int
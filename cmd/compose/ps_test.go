@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestPsFilters(t *testing.T) {
+	containers := []api.ContainerSummary{
+		{Name: "web-1", Service: "web", State: "running", Health: "healthy", Labels: map[string]string{"tier": "front"}},
+		{Name: "web-2", Service: "web", State: "running", Health: "unhealthy", Labels: map[string]string{"tier": "front"}},
+		{Name: "migrate-1", Service: "migrate", State: "exited", ExitCode: 1},
+		{Name: "db-1", Service: "db", State: "exited", ExitCode: 0, Labels: map[string]string{"tier": "back"}},
+	}
+
+	names := func(filter ...string) []string {
+		opts := psOptions{Filter: filter}
+		assert.NilError(t, opts.parseFilter())
+		var names []string
+		for _, c := range opts.filters.apply(containers) {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	assert.DeepEqual(t, names("service=web"), []string{"web-1", "web-2"})
+	assert.DeepEqual(t, names("health=unhealthy"), []string{"web-2"})
+	assert.DeepEqual(t, names("exited=1"), []string{"migrate-1"})
+	assert.DeepEqual(t, names("label=tier"), []string{"web-1", "web-2", "db-1"})
+	assert.DeepEqual(t, names("label=tier=back"), []string{"db-1"})
+	assert.DeepEqual(t, names("service=web", "health=healthy"), []string{"web-1"})
+}
+
+func TestPsFiltersInvalid(t *testing.T) {
+	for _, filter := range []string{"source=registry", "exited=yes", "foo=bar", "status"} {
+		opts := psOptions{Filter: []string{filter}}
+		assert.Assert(t, opts.parseFilter() != nil, filter)
+	}
+}
+
+func TestPsFiltersEmptyJSON(t *testing.T) {
+	for _, filter := range [][]string{nil, {"service=web"}} {
+		opts := psOptions{Filter: filter}
+		assert.NilError(t, opts.parseFilter())
+		var containers []api.ContainerSummary
+		if filter != nil {
+			containers = []api.ContainerSummary{{Name: "db-1", Service: "db"}}
+		}
+		containers = opts.filters.apply(containers)
+		var out bytes.Buffer
+		assert.NilError(t, formatter.Print(containers, formatter.JSON, &out, writter(containers, false)))
+		assert.Equal(t, out.String(), "[]\n")
+	}
+}
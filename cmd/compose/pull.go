@@ -31,6 +31,7 @@ import (
 type pullOptions struct {
 	*projectOptions
 	composeOptions
+	timingsOptions
 	quiet              bool
 	parallel           bool
 	noParallel         bool
@@ -49,7 +50,7 @@ func pullCommand(p *projectOptions, backend api.Service) *cobra.Command {
 			if opts.noParallel {
 				fmt.Fprint(os.Stderr, aec.Apply("option '--no-parallel' is DEPRECATED and will be ignored.\n", aec.RedF))
 			}
-			return nil
+			return opts.validateTimings()
 		}),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
 			return runPull(ctx, backend, opts, args)
@@ -64,6 +65,7 @@ func pullCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	cmd.Flags().BoolVar(&opts.parallel, "no-parallel", true, "DEPRECATED disable parallel pulling.")
 	flags.MarkHidden("no-parallel") //nolint:errcheck
 	cmd.Flags().BoolVar(&opts.ignorePullFailures, "ignore-pull-failures", false, "Pull what it can and ignores images with pull failures")
+	opts.addTimingsFlag(flags)
 	return cmd
 }
 
@@ -86,8 +88,10 @@ func runPull(ctx context.Context, backend api.Service, opts pullOptions, service
 		project.Services = enabled
 	}
 
-	return backend.Pull(ctx, project, api.PullOptions{
-		Quiet:          opts.quiet,
-		IgnoreFailures: opts.ignorePullFailures,
+	return opts.withTimings(ctx, project, func(ctx context.Context) error {
+		return backend.Pull(ctx, project, api.PullOptions{
+			Quiet:          opts.quiet,
+			IgnoreFailures: opts.ignorePullFailures,
+		})
 	})
 }
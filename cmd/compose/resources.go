@@ -0,0 +1,97 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type resourcesListOptions struct {
+	*projectOptions
+	format string
+	quiet  bool
+}
+
+type resourcesListFunc func(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error)
+
+func resourcesListCommand(p *projectOptions, short string, list resourcesListFunc) *cobra.Command {
+	opts := resourcesListOptions{
+		projectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   short,
+		Args:    cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runResourcesList(ctx, list, opts)
+		}),
+		ValidArgsFunction: noCompletion(),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "pretty", "Format the output. Values: [pretty | json]")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display names")
+	return cmd
+}
+
+// toOptionalProject loads the compose model, which is optional when the project name is set
+func (o *projectOptions) toOptionalProject() (string, *types.Project, error) {
+	project, err := o.toProject(nil)
+	if err != nil {
+		if o.ProjectName == "" {
+			return "", nil, err
+		}
+		return o.ProjectName, nil, nil
+	}
+	return project.Name, project, nil
+}
+
+func runResourcesList(ctx context.Context, list resourcesListFunc, opts resourcesListOptions) error {
+	projectName, project, err := opts.toOptionalProject()
+	if err != nil {
+		return err
+	}
+	resources, err := list(ctx, projectName, api.ResourcesListOptions{Project: project})
+	if err != nil {
+		return err
+	}
+	if opts.quiet {
+		for _, r := range resources {
+			fmt.Println(r.Name)
+		}
+		return nil
+	}
+	return formatter.Print(resources, opts.format, os.Stdout, func(w io.Writer) {
+		for _, r := range resources {
+			status := r.Status
+			if status == "" {
+				status = "-"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Name, r.Driver, status, strings.Join(r.Services, ","), strings.Join(r.Containers, ","))
+		}
+	}, "NAME", "DRIVER", "STATUS", "SERVICES", "CONTAINERS")
+}
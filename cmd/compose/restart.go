@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -27,7 +28,9 @@ import (
 
 type restartOptions struct {
 	*projectOptions
-	timeout int
+	timeout        int
+	rolling        bool
+	maxUnavailable int
 }
 
 func restartCommand(p *projectOptions, backend api.Service) *cobra.Command {
@@ -37,6 +40,15 @@ func restartCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	restartCmd := &cobra.Command{
 		Use:   "restart",
 		Short: "Restart containers",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("max-unavailable") && !opts.rolling {
+				return fmt.Errorf("--max-unavailable requires --rolling")
+			}
+			if opts.maxUnavailable < 1 {
+				return fmt.Errorf("--max-unavailable must be at least 1")
+			}
+			return nil
+		},
 		RunE: Adapt(func(ctx context.Context, args []string) error {
 			return runRestart(ctx, backend, opts, args)
 		}),
@@ -44,6 +56,8 @@ func restartCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	}
 	flags := restartCmd.Flags()
 	flags.IntVarP(&opts.timeout, "timeout", "t", 10, "Specify a shutdown timeout in seconds")
+	flags.BoolVar(&opts.rolling, "rolling", false, "Restart containers by batches, waiting for each batch to be running or healthy")
+	flags.IntVar(&opts.maxUnavailable, "max-unavailable", 1, "Number of containers restarted at once by a rolling restart")
 
 	return restartCmd
 }
@@ -56,7 +70,9 @@ func runRestart(ctx context.Context, backend api.Service, opts restartOptions, s
 
 	timeout := time.Duration(opts.timeout) * time.Second
 	return backend.Restart(ctx, project, api.RestartOptions{
-		Timeout:  &timeout,
-		Services: services,
+		Timeout:        &timeout,
+		Services:       services,
+		Rolling:        opts.rolling,
+		MaxUnavailable: opts.maxUnavailable,
 	})
 }
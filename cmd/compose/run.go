@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	cgo "github.com/compose-spec/compose-go/cli"
 	"github.com/compose-spec/compose-go/loader"
@@ -31,7 +32,6 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/compose/v2/pkg/api"
-	"github.com/docker/compose/v2/pkg/progress"
 )
 
 type runOptions struct {
@@ -54,6 +54,7 @@ type runOptions struct {
 	name          string
 	noDeps        bool
 	quietPull     bool
+	waitTimeout   int
 }
 
 func (opts runOptions) apply(project *types.Project) error {
@@ -154,6 +155,7 @@ func runCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	flags.BoolVar(&opts.useAliases, "use-aliases", false, "Use the service's network useAliases in the network(s) the container connects to.")
 	flags.BoolVar(&opts.servicePorts, "service-ports", false, "Run command with the service's ports enabled and mapped to the host.")
 	flags.BoolVar(&opts.quietPull, "quiet-pull", false, "Pull without printing progress information.")
+	flags.IntVar(&opts.waitTimeout, "wait-timeout", 0, "Maximum duration in seconds to wait for dependencies to be ready.")
 
 	flags.SetNormalizeFunc(normalizeRunFlags)
 	flags.SetInterspersed(false)
@@ -176,13 +178,6 @@ func runRun(ctx context.Context, backend api.Service, project *types.Project, op
 		return err
 	}
 
-	err = progress.Run(ctx, func(ctx context.Context) error {
-		return startDependencies(ctx, backend, *project, opts.Service)
-	})
-	if err != nil {
-		return err
-	}
-
 	labels := types.Labels{}
 	for _, s := range opts.labels {
 		parts := strings.SplitN(s, "=", 2)
@@ -212,6 +207,7 @@ func runRun(ctx context.Context, backend api.Service, project *types.Project, op
 		NoDeps:            opts.noDeps,
 		Index:             0,
 		QuietPull:         opts.quietPull,
+		WaitTimeout:       time.Duration(opts.waitTimeout) * time.Second,
 	}
 	exitCode, err := backend.RunOneOffContainer(ctx, project, runOpts)
 	if exitCode != 0 {
@@ -223,22 +219,3 @@ func runRun(ctx context.Context, backend api.Service, project *types.Project, op
 	}
 	return err
 }
-
-func startDependencies(ctx context.Context, backend api.Service, project types.Project, requestedServiceName string) error {
-	dependencies := types.Services{}
-	var requestedService types.ServiceConfig
-	for _, service := range project.Services {
-		if service.Name != requestedServiceName {
-			dependencies = append(dependencies, service)
-		} else {
-			requestedService = service
-		}
-	}
-
-	project.Services = dependencies
-	project.DisabledServices = append(project.DisabledServices, requestedService)
-	if err := backend.Create(ctx, &project, api.CreateOptions{}); err != nil {
-		return err
-	}
-	return backend.Start(ctx, &project, api.StartOptions{})
-}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/pkg/secrets"
+)
+
+func secretsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the local store external secrets are resolved from",
+	}
+	cmd.AddCommand(secretsSetCommand())
+	return cmd
+}
+
+type secretsSetOptions struct {
+	fromFile string
+}
+
+func secretsSetCommand() *cobra.Command {
+	opts := secretsSetOptions{}
+	cmd := &cobra.Command{
+		Use:   "set SECRET",
+		Short: "Encrypt and save a secret in the store set by COMPOSE_SECRETS_STORE, reading it from stdin by default",
+		Args:  cobra.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runSecretsSet(opts, args[0], os.Stdin)
+		}),
+	}
+	cmd.Flags().StringVar(&opts.fromFile, "from-file", "", "Read the secret content from a file")
+	return cmd
+}
+
+func runSecretsSet(opts secretsSetOptions, name string, stdin io.Reader) error {
+	path := os.Getenv(secrets.StoreEnvVar)
+	if path == "" {
+		return fmt.Errorf("%s must be set to the path of the secrets store", secrets.StoreEnvVar)
+	}
+	passphrase := os.Getenv(secrets.PassphraseEnvVar)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set to encrypt the secrets store", secrets.PassphraseEnvVar)
+	}
+	var (
+		content []byte
+		err     error
+	)
+	if opts.fromFile != "" {
+		content, err = ioutil.ReadFile(opts.fromFile)
+	} else {
+		content, err = ioutil.ReadAll(stdin)
+	}
+	if err != nil {
+		return err
+	}
+	return secrets.NewFileStore(path, passphrase).SetSecret(name, content)
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/secrets"
+)
+
+func TestSecretsSet(t *testing.T) {
+	t.Setenv(secrets.HelperEnvVar, "")
+	t.Setenv(secrets.StoreEnvVar, filepath.Join(t.TempDir(), "secrets.json"))
+	t.Setenv(secrets.PassphraseEnvVar, "")
+	err := runSecretsSet(secretsSetOptions{}, "db_password", strings.NewReader("s3cr3t"))
+	assert.Error(t, err, "COMPOSE_SECRETS_PASSPHRASE must be set to encrypt the secrets store")
+
+	t.Setenv(secrets.PassphraseEnvVar, "passphrase")
+	assert.NilError(t, runSecretsSet(secretsSetOptions{}, "db_password", strings.NewReader("s3cr3t")))
+
+	content, err := secrets.ProviderFromEnv().GetSecret(context.Background(), "db_password")
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+}
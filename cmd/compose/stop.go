@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -29,6 +30,7 @@ type stopOptions struct {
 	*projectOptions
 	timeChanged bool
 	timeout     int
+	parallel    int
 }
 
 func stopCommand(p *projectOptions, backend api.Service) *cobra.Command {
@@ -48,11 +50,15 @@ func stopCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.timeout, "timeout", "t", 10, "Specify a shutdown timeout in seconds")
+	flags.IntVar(&opts.parallel, "parallel", 0, "Maximum number of containers to stop concurrently (unlimited when 0)")
 
 	return cmd
 }
 
 func runStop(ctx context.Context, backend api.Service, opts stopOptions, services []string) error {
+	if opts.parallel < 0 {
+		return fmt.Errorf("--parallel must be a positive number")
+	}
 	project, err := opts.toProject(services)
 	if err != nil {
 		return err
@@ -66,5 +72,6 @@ func runStop(ctx context.Context, backend api.Service, opts stopOptions, service
 	return backend.Stop(ctx, project, api.StopOptions{
 		Timeout:  timeout,
 		Services: services,
+		Parallel: opts.parallel,
 	})
 }
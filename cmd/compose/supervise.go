@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type superviseOptions struct {
+	*projectOptions
+	noColor  bool
+	noPrefix bool
+}
+
+func superviseCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	opts := superviseOptions{
+		projectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "supervise [SERVICE...]",
+		Short: "Restart or recreate unhealthy containers according to services autoheal policy",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runSupervise(ctx, backend, opts, args)
+		}),
+		ValidArgsFunction: serviceCompletion(p),
+	}
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.noColor, "no-color", false, "Produce monochrome output.")
+	flags.BoolVar(&opts.noPrefix, "no-log-prefix", false, "Don't print prefix in logs.")
+	return cmd
+}
+
+func runSupervise(ctx context.Context, backend api.Service, opts superviseOptions, services []string) error {
+	project, err := opts.toProject(services)
+	if err != nil {
+		return err
+	}
+	return backend.Supervise(ctx, project, api.SuperviseOptions{
+		Services: services,
+		Consumer: formatter.NewLogConsumer(ctx, os.Stdout, !opts.noColor, !opts.noPrefix),
+	})
+}
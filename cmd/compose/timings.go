@@ -0,0 +1,168 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const (
+	timingsPretty = "pretty"
+	timingsJSON   = "json"
+)
+
+// timingsOptions hold the --timings flag of progress-driven commands
+type timingsOptions struct {
+	timings string
+}
+
+func (o *timingsOptions) addTimingsFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&o.timings, "timings", "", "Print the time spent on each step once done. Values: [pretty | json]")
+	flags.Lookup("timings").NoOptDefVal = timingsPretty
+}
+
+func (o timingsOptions) validateTimings() error {
+	switch o.timings {
+	case "", timingsPretty, timingsJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported --timings value %q", o.timings)
+}
+
+// withTimings records the steps run by fn, then prints a timings report, even if fn failed
+func (o timingsOptions) withTimings(ctx context.Context, project *types.Project, fn func(context.Context) error) error {
+	if o.timings == "" {
+		return fn(ctx)
+	}
+	timings := progress.NewTimings()
+	err := fn(progress.WithTimings(ctx, timings))
+	printErr := printTimings(os.Stdout, o.timings, compose.TimingsReport(project, timings))
+	if err != nil {
+		return err
+	}
+	return printErr
+}
+
+func printTimings(out io.Writer, format string, report api.TimingsReport) error {
+	if format == timingsJSON {
+		return json.NewEncoder(out).Encode(toTimingsJSON(report))
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tPULL\tBUILD\tCREATE\tSTART\tWAIT\tREADY")
+	for _, s := range report.Services {
+		name := s.Service
+		if s.Critical {
+			name += " *"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", name,
+			formatTiming(s.Pull), formatTiming(s.Build), formatTiming(s.Create),
+			formatTiming(s.Start), formatTiming(s.Wait), formatTiming(s.Ready))
+	}
+	if len(report.Resources) > 0 {
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "RESOURCE\tSTEP\tDURATION")
+		for _, r := range report.Resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", r.Resource, r.Step, formatTiming(r.Duration))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(report.CriticalPath) > 0 {
+		fmt.Fprintf(out, "\nCritical path (*): %s\n", strings.Join(report.CriticalPath, " -> "))
+	}
+	_, err := fmt.Fprintf(out, "Total: %s\n", formatTiming(report.Total))
+	return err
+}
+
+func formatTiming(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+type serviceTimingsJSON struct {
+	Service  string  `json:"service"`
+	Pull     float64 `json:"pull"`
+	Build    float64 `json:"build"`
+	Create   float64 `json:"create"`
+	Start    float64 `json:"start"`
+	Wait     float64 `json:"wait"`
+	Ready    float64 `json:"ready"`
+	Critical bool    `json:"critical"`
+}
+
+type resourceTimingJSON struct {
+	Resource string  `json:"resource"`
+	Step     string  `json:"step"`
+	Duration float64 `json:"duration"`
+}
+
+// timingsReportJSON is the JSON timings report, durations are set in seconds
+type timingsReportJSON struct {
+	Total        float64              `json:"total"`
+	CriticalPath []string             `json:"critical_path"`
+	Services     []serviceTimingsJSON `json:"services"`
+	Resources    []resourceTimingJSON `json:"resources"`
+}
+
+func toTimingsJSON(report api.TimingsReport) timingsReportJSON {
+	result := timingsReportJSON{
+		Total:        report.Total.Seconds(),
+		CriticalPath: report.CriticalPath,
+		Services:     []serviceTimingsJSON{},
+		Resources:    []resourceTimingJSON{},
+	}
+	if result.CriticalPath == nil {
+		result.CriticalPath = []string{}
+	}
+	for _, s := range report.Services {
+		result.Services = append(result.Services, serviceTimingsJSON{
+			Service:  s.Service,
+			Pull:     s.Pull.Seconds(),
+			Build:    s.Build.Seconds(),
+			Create:   s.Create.Seconds(),
+			Start:    s.Start.Seconds(),
+			Wait:     s.Wait.Seconds(),
+			Ready:    s.Ready.Seconds(),
+			Critical: s.Critical,
+		})
+	}
+	for _, r := range report.Resources {
+		result.Resources = append(result.Resources, resourceTimingJSON{
+			Resource: r.Resource,
+			Step:     r.Step,
+			Duration: r.Duration.Seconds(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestPrintTimings(t *testing.T) {
+	report := api.TimingsReport{
+		Total: 12500 * time.Millisecond,
+		Services: []api.ServiceTimings{
+			{Service: "db", Pull: 3 * time.Second, Start: 500 * time.Millisecond, Wait: 8 * time.Second, Ready: 11 * time.Second, Critical: true},
+			{Service: "web", Start: time.Second, Ready: 12500 * time.Millisecond, Critical: true},
+		},
+		Resources:    []api.ResourceTiming{{Resource: "network myproject_default", Step: "Creating", Duration: 120 * time.Millisecond}},
+		CriticalPath: []string{"db", "web"},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, printTimings(out, timingsPretty, report))
+	assert.Equal(t, out.String(), `SERVICE   PULL   BUILD   CREATE   START   WAIT   READY
+db *      3s     -       -        500ms   8s     11s
+web *     -      -       -        1s      -      12.5s
+
+RESOURCE                    STEP       DURATION
+network myproject_default   Creating   100ms
+
+Critical path (*): db -> web
+Total: 12.5s
+`)
+
+	out.Reset()
+	assert.NilError(t, printTimings(out, timingsJSON, report))
+	assert.Equal(t, out.String(), `{"total":12.5,"critical_path":["db","web"],"services":[`+
+		`{"service":"db","pull":3,"build":0,"create":0,"start":0.5,"wait":8,"ready":11,"critical":true},`+
+		`{"service":"web","pull":0,"build":0,"create":0,"start":1,"wait":0,"ready":12.5,"critical":true}],`+
+		`"resources":[{"resource":"network myproject_default","step":"Creating","duration":0.12}]}
+`)
+
+	assert.Error(t, timingsOptions{timings: "yaml"}.validateTimings(), `unsupported --timings value "yaml"`)
+}
@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -26,6 +27,8 @@ import (
 	"github.com/docker/compose/v2/cmd/formatter"
 
 	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli/streams"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose/v2/pkg/api"
@@ -39,17 +42,22 @@ type composeOptions struct {
 
 type upOptions struct {
 	*composeOptions
+	timingsOptions
 	Detach             bool
 	noStart            bool
 	noDeps             bool
 	cascadeStop        bool
-	exitCodeFrom       string
+	cascadeFail        bool
+	abortAfterExit     []string
+	exitCodeFrom       []string
 	scale              []string
 	noColor            bool
 	noPrefix           bool
 	attachDependencies bool
 	attach             []string
 	wait               bool
+	ui                 bool
+	menu               bool
 }
 
 func (opts upOptions) apply(project *types.Project, services []string) error {
@@ -66,8 +74,8 @@ func (opts upOptions) apply(project *types.Project, services []string) error {
 		project.Services = enabled
 	}
 
-	if opts.exitCodeFrom != "" {
-		_, err := project.GetService(opts.exitCodeFrom)
+	for _, service := range append(opts.exitCodeFrom, opts.abortAfterExit...) {
+		_, err := project.GetService(service)
 		if err != nil {
 			return err
 		}
@@ -92,6 +100,9 @@ func (opts upOptions) apply(project *types.Project, services []string) error {
 	return nil
 }
 
+// menuEnvVar disables keyboard shortcuts when set to false, unless --menu is set
+const menuEnvVar = "COMPOSE_MENU"
+
 func upCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	up := upOptions{}
 	create := createOptions{}
@@ -100,6 +111,13 @@ func upCommand(p *projectOptions, backend api.Service) *cobra.Command {
 		Short: "Create and start containers",
 		PreRunE: AdaptCmd(func(ctx context.Context, cmd *cobra.Command, args []string) error {
 			create.timeChanged = cmd.Flags().Changed("timeout")
+			if menu, ok := os.LookupEnv(menuEnvVar); ok && !cmd.Flags().Changed("menu") {
+				enabled, err := strconv.ParseBool(menu)
+				if err != nil {
+					return errors.Wrapf(err, "invalid %s value %q", menuEnvVar, menu)
+				}
+				up.menu = enabled
+			}
 			return validateFlags(&up, &create)
 		}),
 		RunE: p.WithServices(func(ctx context.Context, project *types.Project, services []string) error {
@@ -122,9 +140,12 @@ func upCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	flags.BoolVar(&up.noPrefix, "no-log-prefix", false, "Don't print prefix in logs.")
 	flags.BoolVar(&create.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed.")
 	flags.BoolVar(&create.noRecreate, "no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	flags.BoolVar(&create.recreateNetworks, "recreate-networks", false, "Recreate networks which configuration changed, reconnecting containers.")
 	flags.BoolVar(&up.noStart, "no-start", false, "Don't start the services after creating them.")
 	flags.BoolVar(&up.cascadeStop, "abort-on-container-exit", false, "Stops all containers if any container was stopped. Incompatible with -d")
-	flags.StringVar(&up.exitCodeFrom, "exit-code-from", "", "Return the exit code of the selected service container. Implies --abort-on-container-exit")
+	flags.BoolVar(&up.cascadeFail, "abort-on-container-failure", false, "Stops all containers if any container exited with a non-zero exit code. Incompatible with -d")
+	flags.StringArrayVar(&up.abortAfterExit, "abort-after-exit", []string{}, "Stops all containers once all containers of the selected services have exited. Incompatible with -d")
+	flags.StringArrayVar(&up.exitCodeFrom, "exit-code-from", []string{}, "Return the worst exit code of the selected services containers. Implies --abort-on-container-exit, unless another abort policy is set")
 	flags.IntVarP(&create.timeout, "timeout", "t", 10, "Use this timeout in seconds for container shutdown when attached or when containers are already running.")
 	flags.BoolVar(&up.noDeps, "no-deps", false, "Don't start linked services.")
 	flags.BoolVar(&create.recreateDeps, "always-recreate-deps", false, "Recreate dependent containers. Incompatible with --no-recreate.")
@@ -133,25 +154,38 @@ func upCommand(p *projectOptions, backend api.Service) *cobra.Command {
 	flags.BoolVar(&create.quietPull, "quiet-pull", false, "Pull without printing progress information.")
 	flags.StringArrayVar(&up.attach, "attach", []string{}, "Attach to service output.")
 	flags.BoolVar(&up.wait, "wait", false, "Wait for services to be running|healthy. Implies detached mode.")
+	flags.BoolVar(&up.ui, "ui", false, "Display an interactive dashboard of services and their logs.")
+	flags.BoolVar(&up.menu, "menu", true, "Enable keyboard shortcuts when attached to an interactive terminal. Can be set with "+menuEnvVar+".")
+	up.addTimingsFlag(flags)
 
 	return upCmd
 }
 
 func validateFlags(up *upOptions, create *createOptions) error {
-	if up.exitCodeFrom != "" {
+	if up.cascadeStop && (up.cascadeFail || len(up.abortAfterExit) > 0) {
+		return fmt.Errorf("--abort-on-container-exit cannot be combined with --abort-on-container-failure or --abort-after-exit")
+	}
+	if up.cascadeFail && len(up.abortAfterExit) > 0 {
+		return fmt.Errorf("--abort-on-container-failure and --abort-after-exit are incompatible")
+	}
+	if len(up.exitCodeFrom) > 0 && !up.cascadeFail && len(up.abortAfterExit) == 0 {
 		up.cascadeStop = true
 	}
+	abort := up.cascadeStop || up.cascadeFail || len(up.abortAfterExit) > 0
 	if up.wait {
-		if up.attachDependencies || up.cascadeStop || len(up.attach) > 0 {
-			return fmt.Errorf("--wait cannot be combined with --abort-on-container-exit, --attach or --attach-dependencies")
+		if up.attachDependencies || abort || len(up.attach) > 0 {
+			return fmt.Errorf("--wait cannot be combined with --abort-on-container-exit, --abort-on-container-failure, --abort-after-exit, --attach or --attach-dependencies")
 		}
 		up.Detach = true
 	}
 	if create.Build && create.noBuild {
 		return fmt.Errorf("--build and --no-build are incompatible")
 	}
-	if up.Detach && (up.attachDependencies || up.cascadeStop || len(up.attach) > 0) {
-		return fmt.Errorf("--detach cannot be combined with --abort-on-container-exit, --attach or --attach-dependencies")
+	if up.Detach && (up.attachDependencies || abort || len(up.attach) > 0) {
+		return fmt.Errorf("--detach cannot be combined with --abort-on-container-exit, --abort-on-container-failure, --abort-after-exit, --attach or --attach-dependencies")
+	}
+	if up.ui && (up.Detach || up.noStart) {
+		return fmt.Errorf("--ui cannot be combined with --detach, --wait or --no-start")
 	}
 	if create.forceRecreate && create.noRecreate {
 		return fmt.Errorf("--force-recreate and --no-recreate are incompatible")
@@ -159,7 +193,7 @@ func validateFlags(up *upOptions, create *createOptions) error {
 	if create.recreateDeps && create.noRecreate {
 		return fmt.Errorf("--always-recreate-deps and --no-recreate are incompatible")
 	}
-	return nil
+	return up.validateTimings()
 }
 
 func runUp(ctx context.Context, backend api.Service, createOptions createOptions, upOptions upOptions, project *types.Project, services []string) error {
@@ -175,8 +209,22 @@ func runUp(ctx context.Context, backend api.Service, createOptions createOptions
 	}
 
 	var consumer api.LogConsumer
-	if !upOptions.Detach {
-		consumer = formatter.NewLogConsumer(ctx, os.Stdout, !upOptions.noColor, !upOptions.noPrefix)
+	shortcuts := false
+	if upOptions.ui {
+		if !streams.NewIn(os.Stdin).IsTerminal() || !streams.NewOut(os.Stdout).IsTerminal() {
+			return fmt.Errorf("--ui requires an interactive terminal")
+		}
+		dashboard := newDashboard(ctx, backend, project)
+		defer dashboard.Close()
+		consumer = dashboard
+	} else if !upOptions.Detach {
+		var out io.Writer = os.Stdout
+		if upOptions.menu && streams.NewIn(os.Stdin).IsTerminal() && streams.NewOut(os.Stdout).IsTerminal() {
+			// keyboard shortcuts set the terminal in raw mode
+			shortcuts = true
+			out = utils.GetCRLFWriter(os.Stdout)
+		}
+		consumer = formatter.NewLogConsumer(ctx, out, !upOptions.noColor, !upOptions.noPrefix)
 	}
 
 	attachTo := services
@@ -196,21 +244,27 @@ func runUp(ctx context.Context, backend api.Service, createOptions createOptions
 		Inherit:              !createOptions.noInherit,
 		Timeout:              createOptions.GetTimeout(),
 		QuietPull:            createOptions.quietPull,
+		RecreateNetworks:     createOptions.recreateNetworks,
 	}
 
-	if upOptions.noStart {
-		return backend.Create(ctx, project, create)
-	}
+	return upOptions.withTimings(ctx, project, func(ctx context.Context) error {
+		if upOptions.noStart {
+			return backend.Create(ctx, project, create)
+		}
 
-	return backend.Up(ctx, project, api.UpOptions{
-		Create: create,
-		Start: api.StartOptions{
-			Attach:       consumer,
-			AttachTo:     attachTo,
-			ExitCodeFrom: upOptions.exitCodeFrom,
-			CascadeStop:  upOptions.cascadeStop,
-			Wait:         upOptions.wait,
-		},
+		return backend.Up(ctx, project, api.UpOptions{
+			Create: create,
+			Start: api.StartOptions{
+				Attach:         consumer,
+				AttachTo:       attachTo,
+				ExitCodesFrom:  upOptions.exitCodeFrom,
+				CascadeStop:    upOptions.cascadeStop,
+				CascadeFail:    upOptions.cascadeFail,
+				AbortAfterExit: upOptions.abortAfterExit,
+				Wait:           upOptions.wait,
+				Shortcuts:      shortcuts,
+			},
+		})
 	})
 }
 
@@ -41,3 +41,19 @@ func TestApplyScaleOpt(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, *foo.Deploy.Replicas, uint64(2))
 }
+
+func TestValidateAbortFlags(t *testing.T) {
+	up := upOptions{exitCodeFrom: []string{"test"}}
+	assert.NilError(t, validateFlags(&up, &createOptions{}))
+	assert.Assert(t, up.cascadeStop)
+
+	up = upOptions{exitCodeFrom: []string{"test"}, abortAfterExit: []string{"test"}}
+	assert.NilError(t, validateFlags(&up, &createOptions{}))
+	assert.Assert(t, !up.cascadeStop)
+
+	up = upOptions{cascadeStop: true, cascadeFail: true}
+	assert.ErrorContains(t, validateFlags(&up, &createOptions{}), "cannot be combined")
+
+	up = upOptions{cascadeFail: true, Detach: true}
+	assert.ErrorContains(t, validateFlags(&up, &createOptions{}), "--detach cannot be combined")
+}
@@ -0,0 +1,189 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type volumesTransferOptions struct {
+	*projectOptions
+	file    string
+	stop    bool
+	timeout int
+	image   string
+}
+
+func (opts volumesTransferOptions) stopTimeout() *time.Duration {
+	timeout := time.Duration(opts.timeout) * time.Second
+	return &timeout
+}
+
+func volumesCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volumes",
+		Short: "Manage project volumes",
+	}
+	cmd.AddCommand(
+		resourcesListCommand(p, "List project volumes", func(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error) {
+			return backend.VolumesList(ctx, projectName, options)
+		}),
+		volumesPruneCommand(p, backend),
+		volumesBackupCommand(p, backend),
+		volumesRestoreCommand(p, backend),
+	)
+	return cmd
+}
+
+type volumesPruneOptions struct {
+	*projectOptions
+	force  bool
+	dryRun bool
+}
+
+func volumesPruneCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	opts := volumesPruneOptions{
+		projectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove orphaned project volumes",
+		Long: `Remove orphaned project volumes
+
+Orphaned volumes were created for the project, but are not declared by the Compose file anymore.
+Volumes still used by a container are kept.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			project, err := opts.toProject(nil)
+			if err != nil {
+				return err
+			}
+			return backend.VolumesPrune(ctx, project, api.VolumesPruneOptions{
+				Force:  opts.force,
+				DryRun: opts.dryRun,
+			})
+		}),
+		ValidArgsFunction: noCompletion(),
+	}
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.force, "force", "f", false, "Don't ask to confirm removal")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Only list volumes to be removed")
+	return cmd
+}
+
+func volumesBackupCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	opts := volumesTransferOptions{
+		projectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "backup [VOLUME...]",
+		Short: "Archive project volumes data",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesBackup(ctx, backend, opts, args)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.file, "output", "o", "", `Archive file to write ("-" for stdout)`)
+	addVolumesTransferFlags(cmd, &opts)
+	return cmd
+}
+
+func volumesRestoreCommand(p *projectOptions, backend api.Service) *cobra.Command {
+	opts := volumesTransferOptions{
+		projectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "restore [VOLUME...]",
+		Short: "Restore project volumes data from an archive",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumesRestore(ctx, backend, opts, args)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.file, "input", "i", "", `Archive file to read ("-" for stdin)`)
+	addVolumesTransferFlags(cmd, &opts)
+	return cmd
+}
+
+func addVolumesTransferFlags(cmd *cobra.Command, opts *volumesTransferOptions) {
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.stop, "stop", false, "Stop services using the volumes during the operation, and start them afterwards")
+	flags.IntVarP(&opts.timeout, "timeout", "t", 10, "Specify a shutdown timeout in seconds")
+	flags.StringVar(&opts.image, "image", "", "Image of the helper container accessing volumes data (default \"busybox\")")
+}
+
+func runVolumesBackup(ctx context.Context, backend api.Service, opts volumesTransferOptions, volumes []string) error {
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	var w io.Writer
+	switch opts.file {
+	case "":
+		return errors.New("an output file is required, use --output")
+	case "-":
+		w = os.Stdout
+	default:
+		f, err := os.OpenFile(opts.file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		w = f
+	}
+	return backend.VolumesBackup(ctx, projectName, w, api.VolumesBackupOptions{
+		Volumes: volumes,
+		Stop:    opts.stop,
+		Timeout: opts.stopTimeout(),
+		Image:   opts.image,
+	})
+}
+
+func runVolumesRestore(ctx context.Context, backend api.Service, opts volumesTransferOptions, volumes []string) error {
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	var r io.Reader
+	switch opts.file {
+	case "":
+		return errors.New("an input file is required, use --input")
+	case "-":
+		r = os.Stdin
+	default:
+		f, err := os.Open(opts.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		r = f
+	}
+	return backend.VolumesRestore(ctx, projectName, r, api.VolumesRestoreOptions{
+		Volumes: volumes,
+		Stop:    opts.stop,
+		Timeout: opts.stopTimeout(),
+		Image:   opts.image,
+	})
+}
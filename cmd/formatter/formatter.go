@@ -24,6 +24,7 @@ import (
 
 	"github.com/docker/compose/v2/pkg/api"
 
+	"github.com/docker/cli/templates"
 	"github.com/pkg/errors"
 )
 
@@ -67,7 +68,34 @@ func Print(toJSON interface{}, format string, outWriter io.Writer, writerFn func
 			_, _ = fmt.Fprintln(outWriter, outJSON)
 		}
 	default:
+		if strings.Contains(format, "{{") {
+			return printTemplate(toJSON, format, outWriter)
+		}
 		return errors.Wrapf(api.ErrParsingFailed, "format value %q could not be parsed", format)
 	}
 	return nil
 }
+
+// printTemplate renders a Go template for each element of a slice, or once for any other value
+func printTemplate(data interface{}, format string, outWriter io.Writer) error {
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return errors.Wrapf(api.ErrParsingFailed, "template parsing error: %v", err)
+	}
+	var items []interface{}
+	if reflect.TypeOf(data).Kind() == reflect.Slice {
+		s := reflect.ValueOf(data)
+		for i := 0; i < s.Len(); i++ {
+			items = append(items, s.Index(i).Interface())
+		}
+	} else {
+		items = append(items, data)
+	}
+	for _, item := range items {
+		if err := tmpl.Execute(outWriter, item); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(outWriter)
+	}
+	return nil
+}
@@ -70,4 +70,11 @@ func TestPrint(t *testing.T) {
 	assert.Equal(t, json, `{"Name":"myName1","Status":"myStatus1"}
 {"Name":"myName2","Status":"myStatus2"}
 `)
+
+	b.Reset()
+	assert.NilError(t, Print(testList, "{{.Name}} is {{.Status}}", b, nil))
+	assert.Equal(t, b.String(), "myName1 is myStatus1\nmyName2 is myStatus2\n")
+
+	b.Reset()
+	assert.ErrorContains(t, Print(testList, "{{.Name", b, nil), "template parsing error")
 }
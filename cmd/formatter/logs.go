@@ -18,11 +18,13 @@ package formatter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/compose/v2/pkg/api"
 )
@@ -111,6 +113,61 @@ type logConsumer struct {
 	prefix     bool
 }
 
+// NewJSONLogConsumer creates a LogConsumer writing each log line as a JSON object
+func NewJSONLogConsumer(ctx context.Context, w io.Writer) api.LogConsumer {
+	return &jsonLogConsumer{
+		ctx:     ctx,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+type jsonLogConsumer struct {
+	ctx     context.Context
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+type jsonLogEntry struct {
+	Service   string    `json:"service"`
+	Container string    `json:"container"`
+	Replica   int       `json:"replica,omitempty"`
+	Stream    string    `json:"stream,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// LogEntry implements api.LogEntryConsumer
+func (l *jsonLogConsumer) LogEntry(entry api.LogEntry) {
+	if l.ctx.Err() != nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_ = l.encoder.Encode(jsonLogEntry{
+		Service:   entry.Service,
+		Container: entry.Container,
+		Replica:   entry.Replica,
+		Stream:    entry.Stream,
+		Timestamp: entry.Timestamp,
+		Message:   entry.Message,
+	})
+}
+
+// Log is used for messages without metadata, which are encoded with a collection timestamp
+func (l *jsonLogConsumer) Log(container, service, message string) {
+	l.LogEntry(api.LogEntry{
+		Service:   service,
+		Container: container,
+		Timestamp: time.Now(),
+		Message:   message,
+	})
+}
+
+// Status is ignored as JSON output only contains container logs
+func (l *jsonLogConsumer) Status(container, msg string) {}
+
+func (l *jsonLogConsumer) Register(container string) {}
+
 type presenter struct {
 	colors colorFunc
 	name   string
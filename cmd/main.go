@@ -17,6 +17,8 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 
 	dockercli "github.com/docker/cli/cli"
@@ -24,12 +26,15 @@ import (
 	"github.com/docker/cli/cli-plugins/plugin"
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/docker/compose/v2/cmd/compatibility"
 	commands "github.com/docker/compose/v2/cmd/compose"
 	"github.com/docker/compose/v2/internal"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/compose"
+	"github.com/docker/compose/v2/pkg/secrets"
+	"github.com/docker/compose/v2/pkg/tracing"
 )
 
 func init() {
@@ -38,33 +43,72 @@ func init() {
 }
 
 func pluginMain() {
-	plugin.Run(func(dockerCli command.Cli) *cobra.Command {
-		lazyInit := api.NewServiceProxy()
-		cmd := commands.RootCommand(lazyInit)
-		originalPreRun := cmd.PersistentPreRunE
-		cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-			if err := plugin.PersistentPreRunE(cmd, args); err != nil {
-				return err
+	dockerCli, err := command.NewDockerCli()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// set when traces are exported, shut down once the command completed to flush batched spans
+	var provider *sdktrace.TracerProvider
+	lazyInit := api.NewServiceProxy()
+	cmd := commands.RootCommand(lazyInit)
+	originalPreRun := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := plugin.PersistentPreRunE(cmd, args); err != nil {
+			return err
+		}
+		traced, err := tracing.InitProvider(cmd.Context())
+		if err != nil {
+			return err
+		}
+		apiClient := dockerCli.Client()
+		if traced != nil {
+			provider = traced
+			apiClient = tracing.WrapClient(apiClient, traced)
+		}
+		var backend api.Service = compose.NewComposeService(apiClient, dockerCli.ConfigFile(),
+			compose.WithSecretProvider(secrets.ProviderFromEnv()))
+		if traced != nil {
+			backend = tracing.WrapService(backend, traced)
+		}
+		lazyInit.WithService(backend)
+		lazyInit.WithInterceptor(compose.NewExecutableInterceptor(dockerCli.ConfigFile()))
+		if originalPreRun != nil {
+			return originalPreRun(cmd, args)
+		}
+		return nil
+	}
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return dockercli.StatusError{
+			StatusCode: compose.CommandSyntaxFailure.ExitCode,
+			Status:     err.Error(),
+		}
+	})
+
+	err = plugin.RunPlugin(dockerCli, cmd, manager.Metadata{
+		SchemaVersion: "0.1.0",
+		Vendor:        "Docker Inc.",
+		Version:       internal.Version,
+	})
+	if provider != nil {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			fmt.Fprintln(dockerCli.Err(), err)
+		}
+	}
+	if err != nil {
+		// exit as plugin.Run does, which can't be used as it exits before traces are flushed
+		if sterr, ok := err.(dockercli.StatusError); ok {
+			if sterr.Status != "" {
+				fmt.Fprintln(dockerCli.Err(), sterr.Status)
 			}
-			lazyInit.WithService(compose.NewComposeService(dockerCli.Client(), dockerCli.ConfigFile()))
-			if originalPreRun != nil {
-				return originalPreRun(cmd, args)
+			if sterr.StatusCode == 0 {
+				os.Exit(1)
 			}
-			return nil
+			os.Exit(sterr.StatusCode)
 		}
-		cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
-			return dockercli.StatusError{
-				StatusCode: compose.CommandSyntaxFailure.ExitCode,
-				Status:     err.Error(),
-			}
-		})
-		return cmd
-	},
-		manager.Metadata{
-			SchemaVersion: "0.1.0",
-			Vendor:        "Docker Inc.",
-			Version:       internal.Version,
-		})
+		fmt.Fprintln(dockerCli.Err(), err)
+		os.Exit(1)
+	}
 }
 
 func main() {
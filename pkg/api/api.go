@@ -76,6 +76,20 @@ type Service interface {
 	Port(ctx context.Context, project string, service string, port int, options PortOptions) (string, int, error)
 	// Images executes the equivalent of a `compose images`
 	Images(ctx context.Context, projectName string, options ImagesOptions) ([]ImageSummary, error)
+	// VolumesBackup writes an archive of project volumes data to w
+	VolumesBackup(ctx context.Context, projectName string, w io.Writer, options VolumesBackupOptions) error
+	// VolumesRestore restores project volumes data from an archive produced by VolumesBackup
+	VolumesRestore(ctx context.Context, projectName string, r io.Reader, options VolumesRestoreOptions) error
+	// VolumesList lists volumes owned by a project or declared as external by its compose model
+	VolumesList(ctx context.Context, projectName string, options ResourcesListOptions) ([]ResourceSummary, error)
+	// VolumesPrune removes orphaned project volumes
+	VolumesPrune(ctx context.Context, project *types.Project, options VolumesPruneOptions) error
+	// NetworksList lists networks owned by a project or declared as external by its compose model
+	NetworksList(ctx context.Context, projectName string, options ResourcesListOptions) ([]ResourceSummary, error)
+	// Supervise applies the autoheal policies of project services until ctx is done
+	Supervise(ctx context.Context, project *types.Project, options SuperviseOptions) error
+	// Lint executes the equivalent to a `compose lint`
+	Lint(ctx context.Context, project *types.Project, options LintOptions) ([]LintFinding, error)
 }
 
 // BuildOptions group options of the Build API
@@ -112,6 +126,8 @@ type CreateOptions struct {
 	Timeout *time.Duration
 	// QuietPull makes the pulling process quiet
 	QuietPull bool
+	// RecreateNetworks removes and recreates networks which configuration drifted from the model
+	RecreateNetworks bool
 }
 
 // StartOptions group options of the Start API
@@ -122,10 +138,19 @@ type StartOptions struct {
 	AttachTo []string
 	// CascadeStop stops the application when a container stops
 	CascadeStop bool
+	// CascadeFail stops the application when a container exits with a non-zero exit code
+	CascadeFail bool
+	// AbortAfterExit stops the application once all containers of these services have exited
+	AbortAfterExit []string
 	// ExitCodeFrom return exit code from specified service
 	ExitCodeFrom string
+	// ExitCodesFrom return the worst exit code from specified services, along with ExitCodeFrom
+	ExitCodesFrom []string
 	// Wait won't return until containers reached the running|healthy state
 	Wait bool
+	// Shortcuts reads keyboard shortcuts from the terminal while attached. As the terminal is then set in raw mode,
+	// Attach must write lines terminated by "\r\n"
+	Shortcuts bool
 }
 
 // RestartOptions group options of the Restart API
@@ -134,6 +159,10 @@ type RestartOptions struct {
 	Timeout *time.Duration
 	// Services passed in the command line to be restarted
 	Services []string
+	// Rolling restarts service containers by batches, waiting for each batch to be running or healthy
+	Rolling bool
+	// MaxUnavailable is the number of containers restarted at once by a rolling restart
+	MaxUnavailable int
 }
 
 // StopOptions group options of the Stop API
@@ -142,6 +171,8 @@ type StopOptions struct {
 	Timeout *time.Duration
 	// Services passed in the command line to be stopped
 	Services []string
+	// Parallel limits the number of containers being stopped concurrently, unlimited when zero
+	Parallel int
 }
 
 // UpOptions group options of the Up API
@@ -229,8 +260,12 @@ type RunOptions struct {
 	NoDeps            bool
 	// QuietPull makes the pulling process quiet
 	QuietPull bool
+	// WaitTimeout limits the time to wait for dependencies to meet their condition
+	WaitTimeout time.Duration
 	// used by exec
 	Index int
+	// ContainerID is the container exec runs in, instead of the service container at Index
+	ContainerID string
 }
 
 // EventsOptions group options of the Events API
@@ -239,6 +274,14 @@ type EventsOptions struct {
 	Consumer func(event Event) error
 }
 
+// SuperviseOptions group options of the Supervise API
+type SuperviseOptions struct {
+	// Services restricts supervision to these services, all services with a policy are supervised when empty
+	Services []string
+	// Consumer receives the actions taken on containers
+	Consumer LogConsumer
+}
+
 // Event is a container runtime event served by Events API
 type Event struct {
 	Timestamp  time.Time
@@ -267,6 +310,8 @@ func (e Event) String() string {
 // ListOptions group options of the ls API
 type ListOptions struct {
 	All bool
+	// Labels only select projects with containers matching all those label filters (`key` or `key=value`)
+	Labels []string
 }
 
 // PsOptions group options of the Ps API
@@ -285,6 +330,131 @@ type CopyOptions struct {
 	CopyUIDGID  bool
 }
 
+// VolumesBackupOptions group options of the volumes backup API
+type VolumesBackupOptions struct {
+	// Volumes selects project volumes by their name in the compose model, all project volumes if empty
+	Volumes []string
+	// Stop services using the volumes during backup, and start them afterwards
+	Stop bool
+	// Timeout to stop services
+	Timeout *time.Duration
+	// Image used by the helper container accessing volumes data
+	Image string
+}
+
+// VolumesRestoreOptions group options of the volumes restore API
+type VolumesRestoreOptions struct {
+	// Volumes selects volumes from the archive by their name in the compose model, all archived volumes if empty
+	Volumes []string
+	// Stop services using the volumes during restore, and start them afterwards
+	Stop bool
+	// Timeout to stop services
+	Timeout *time.Duration
+	// Image used by the helper container accessing volumes data
+	Image string
+}
+
+// ResourcesListOptions group options of the VolumesList and NetworksList APIs
+type ResourcesListOptions struct {
+	// Project is the compose model resources are compared to. Might be nil if user only set a project name
+	Project *types.Project
+}
+
+// VolumesPruneOptions group options of the VolumesPrune API
+type VolumesPruneOptions struct {
+	// DryRun just list removable volumes
+	DryRun bool
+	// Force don't ask to confirm removal
+	Force bool
+}
+
+const (
+	// ResourceDeclared is the status of a resource declared by the compose model
+	ResourceDeclared = "declared"
+	// ResourceExternal is the status of a resource declared as external by the compose model
+	ResourceExternal = "external"
+	// ResourceOrphaned is the status of a project resource not declared by the compose model anymore
+	ResourceOrphaned = "orphaned"
+)
+
+// ResourceSummary hold high-level description of a project volume or network
+type ResourceSummary struct {
+	Name   string
+	Driver string
+	Labels map[string]string
+	// Services using the resource
+	Services []string
+	// Containers using the resource
+	Containers []string
+	// Status compares the resource with the compose model, empty if the model is unknown
+	Status string
+}
+
+// TimingsReport sums up the time spent by a command on project services and resources
+type TimingsReport struct {
+	// Total is the time elapsed until the last step completed
+	Total     time.Duration
+	Services  []ServiceTimings
+	Resources []ResourceTiming
+	// CriticalPath lists, in dependency order, the services which delayed the command the most
+	CriticalPath []string
+}
+
+// ServiceTimings hold the time spent on each step of a service, the longest one is kept for services with replicas
+type ServiceTimings struct {
+	Service string
+	Pull    time.Duration
+	Build   time.Duration
+	Create  time.Duration
+	Start   time.Duration
+	// Wait is the time dependent services waited for the service to meet its depends_on condition
+	Wait time.Duration
+	// Ready is the time elapsed until the service completed its last step
+	Ready    time.Duration
+	Critical bool
+}
+
+// ResourceTiming hold the time spent on a project network or volume
+type ResourceTiming struct {
+	Resource string
+	Step     string
+	Duration time.Duration
+}
+
+const (
+	// LintLevelError is the level of findings which make lint fail
+	LintLevelError = "error"
+	// LintLevelWarning is the level of findings about risky configurations
+	LintLevelWarning = "warning"
+	// LintLevelNote is the level of informational findings
+	LintLevelNote = "note"
+	// LintLevelOff disables a lint rule
+	LintLevelOff = "off"
+)
+
+// LintOptions group options of the Lint API
+type LintOptions struct {
+	// Levels override the level of rules by ID, taking precedence over the project x-lint extension
+	Levels map[string]string
+}
+
+// LintRule is a check run over the compose model
+type LintRule struct {
+	ID          string
+	Description string
+	Level       string
+}
+
+// LintFinding is a problem reported by a lint rule, located in the compose file declaring it when known
+type LintFinding struct {
+	Rule    string
+	Level   string
+	Message string
+	File    string
+	Line    int
+	Column  int
+}
+
 // PortPublisher hold status about published port
 type PortPublisher struct {
 	URL           string
@@ -303,7 +473,24 @@ type ContainerSummary struct {
 	State      string
 	Health     string
 	ExitCode   int
+	Labels     map[string]string
 	Publishers PortPublishers
+	// RestartCount is the number of times the engine restarted the container
+	RestartCount int
+	// OOMKilled is set when the container process was killed for exceeding its memory limit
+	OOMKilled  bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// HealthLog holds the last healthcheck probe results, oldest first
+	HealthLog []HealthcheckResult
+}
+
+// HealthcheckResult holds the result of a single healthcheck probe
+type HealthcheckResult struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
 }
 
 // PortPublishers is a slice of PortPublisher
@@ -370,6 +557,12 @@ type LogOptions struct {
 	Until      string
 	Follow     bool
 	Timestamps bool
+	// Grep only select log lines matching this regular expression
+	Grep string
+	// Level only select JSON log lines with a level at least as severe, lines without a level are kept
+	Level string
+	// Ordered merges logs from all containers by timestamp
+	Ordered bool
 }
 
 // PauseOptions group options of the Pause API
@@ -408,6 +601,14 @@ type Stack struct {
 	Name   string
 	Status string
 	Reason string
+	// ConfigFiles is the comma-separated list of compose files used to create the stack
+	ConfigFiles string
+	// WorkingDir is the project working directory used to create the stack
+	WorkingDir string
+	// Version is the compose version which created the stack containers
+	Version string
+	// Services hold running (Replicas) and existing (Desired) containers count per service
+	Services []ServiceStatus
 }
 
 // LogConsumer is a callback to process log messages from services
@@ -417,6 +618,22 @@ type LogConsumer interface {
 	Register(container string)
 }
 
+// LogEntry is a log line collected from a service container, with its metadata
+type LogEntry struct {
+	Service   string
+	Container string
+	Replica   int
+	// Stream is either "stdout" or "stderr"
+	Stream    string
+	Timestamp time.Time
+	Message   string
+}
+
+// LogEntryConsumer can be implemented by a LogConsumer to receive log lines as LogEntry rather than raw text
+type LogEntryConsumer interface {
+	LogEntry(entry LogEntry)
+}
+
 // ContainerEventListener is a callback to process ContainerEvent from services
 type ContainerEventListener func(event ContainerEvent)
 
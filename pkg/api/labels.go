@@ -53,6 +53,8 @@ const (
 	DependenciesLabel = "com.docker.compose.depends_on"
 	// VersionLabel stores the compose tool version used to run application
 	VersionLabel = "com.docker.compose.version"
+	// PreStopLabel stores the hooks to run inside a container before it is stopped
+	PreStopLabel = "com.docker.compose.pre_stop"
 )
 
 // ComposeVersion is the compose tool version as declared by label VersionLabel
@@ -18,6 +18,7 @@ package api
 
 import (
 	"context"
+	"io"
 
 	"github.com/compose-spec/compose-go/types"
 )
@@ -48,6 +49,13 @@ type ServiceProxy struct {
 	EventsFn             func(ctx context.Context, project string, options EventsOptions) error
 	PortFn               func(ctx context.Context, project string, service string, port int, options PortOptions) (string, int, error)
 	ImagesFn             func(ctx context.Context, projectName string, options ImagesOptions) ([]ImageSummary, error)
+	VolumesBackupFn      func(ctx context.Context, projectName string, w io.Writer, options VolumesBackupOptions) error
+	VolumesRestoreFn     func(ctx context.Context, projectName string, r io.Reader, options VolumesRestoreOptions) error
+	VolumesListFn        func(ctx context.Context, projectName string, options ResourcesListOptions) ([]ResourceSummary, error)
+	VolumesPruneFn       func(ctx context.Context, project *types.Project, options VolumesPruneOptions) error
+	NetworksListFn       func(ctx context.Context, projectName string, options ResourcesListOptions) ([]ResourceSummary, error)
+	SuperviseFn          func(ctx context.Context, project *types.Project, options SuperviseOptions) error
+	LintFn               func(ctx context.Context, project *types.Project, options LintOptions) ([]LintFinding, error)
 	interceptors         []Interceptor
 }
 
@@ -56,8 +64,9 @@ func NewServiceProxy() *ServiceProxy {
 	return &ServiceProxy{}
 }
 
-// Interceptor allow to customize the compose types.Project before the actual Service method is executed
-type Interceptor func(ctx context.Context, project *types.Project)
+// Interceptor allow to customize the compose types.Project before the actual Service method is executed, or to
+// reject it with an error. command is the compose command the method implements, like `up` or `create`
+type Interceptor func(ctx context.Context, command string, project *types.Project) error
 
 var _ Service = &ServiceProxy{}
 
@@ -87,6 +96,13 @@ func (s *ServiceProxy) WithService(service Service) *ServiceProxy {
 	s.EventsFn = service.Events
 	s.PortFn = service.Port
 	s.ImagesFn = service.Images
+	s.VolumesBackupFn = service.VolumesBackup
+	s.VolumesRestoreFn = service.VolumesRestore
+	s.VolumesListFn = service.VolumesList
+	s.VolumesPruneFn = service.VolumesPrune
+	s.NetworksListFn = service.NetworksList
+	s.SuperviseFn = service.Supervise
+	s.LintFn = service.Lint
 	return s
 }
 
@@ -96,13 +112,22 @@ func (s *ServiceProxy) WithInterceptor(interceptors ...Interceptor) *ServiceProx
 	return s
 }
 
+func (s *ServiceProxy) intercept(ctx context.Context, command string, project *types.Project) error {
+	for _, i := range s.interceptors {
+		if err := i(ctx, command, project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Build implements Service interface
 func (s *ServiceProxy) Build(ctx context.Context, project *types.Project, options BuildOptions) error {
 	if s.BuildFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "build", project); err != nil {
+		return err
 	}
 	return s.BuildFn(ctx, project, options)
 }
@@ -112,8 +137,8 @@ func (s *ServiceProxy) Push(ctx context.Context, project *types.Project, options
 	if s.PushFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "push", project); err != nil {
+		return err
 	}
 	return s.PushFn(ctx, project, options)
 }
@@ -123,8 +148,8 @@ func (s *ServiceProxy) Pull(ctx context.Context, project *types.Project, options
 	if s.PullFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "pull", project); err != nil {
+		return err
 	}
 	return s.PullFn(ctx, project, options)
 }
@@ -134,8 +159,8 @@ func (s *ServiceProxy) Create(ctx context.Context, project *types.Project, optio
 	if s.CreateFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "create", project); err != nil {
+		return err
 	}
 	return s.CreateFn(ctx, project, options)
 }
@@ -145,8 +170,8 @@ func (s *ServiceProxy) Start(ctx context.Context, project *types.Project, option
 	if s.StartFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "start", project); err != nil {
+		return err
 	}
 	return s.StartFn(ctx, project, options)
 }
@@ -156,8 +181,8 @@ func (s *ServiceProxy) Restart(ctx context.Context, project *types.Project, opti
 	if s.RestartFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "restart", project); err != nil {
+		return err
 	}
 	return s.RestartFn(ctx, project, options)
 }
@@ -167,8 +192,8 @@ func (s *ServiceProxy) Stop(ctx context.Context, project *types.Project, options
 	if s.StopFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "stop", project); err != nil {
+		return err
 	}
 	return s.StopFn(ctx, project, options)
 }
@@ -178,8 +203,8 @@ func (s *ServiceProxy) Up(ctx context.Context, project *types.Project, options U
 	if s.UpFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "up", project); err != nil {
+		return err
 	}
 	return s.UpFn(ctx, project, options)
 }
@@ -221,8 +246,8 @@ func (s *ServiceProxy) Convert(ctx context.Context, project *types.Project, opti
 	if s.ConvertFn == nil {
 		return nil, ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "convert", project); err != nil {
+		return nil, err
 	}
 	return s.ConvertFn(ctx, project, options)
 }
@@ -232,8 +257,8 @@ func (s *ServiceProxy) Kill(ctx context.Context, project *types.Project, options
 	if s.KillFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "kill", project); err != nil {
+		return err
 	}
 	return s.KillFn(ctx, project, options)
 }
@@ -243,8 +268,8 @@ func (s *ServiceProxy) RunOneOffContainer(ctx context.Context, project *types.Pr
 	if s.RunOneOffContainerFn == nil {
 		return 0, ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "run", project); err != nil {
+		return 0, err
 	}
 	return s.RunOneOffContainerFn(ctx, project, options)
 }
@@ -254,8 +279,8 @@ func (s *ServiceProxy) Remove(ctx context.Context, project *types.Project, optio
 	if s.RemoveFn == nil {
 		return ErrNotImplemented
 	}
-	for _, i := range s.interceptors {
-		i(ctx, project)
+	if err := s.intercept(ctx, "rm", project); err != nil {
+		return err
 	}
 	return s.RemoveFn(ctx, project, options)
 }
@@ -323,3 +348,68 @@ func (s *ServiceProxy) Images(ctx context.Context, project string, options Image
 	}
 	return s.ImagesFn(ctx, project, options)
 }
+
+// VolumesBackup implements Service interface
+func (s *ServiceProxy) VolumesBackup(ctx context.Context, projectName string, w io.Writer, options VolumesBackupOptions) error {
+	if s.VolumesBackupFn == nil {
+		return ErrNotImplemented
+	}
+	return s.VolumesBackupFn(ctx, projectName, w, options)
+}
+
+// VolumesRestore implements Service interface
+func (s *ServiceProxy) VolumesRestore(ctx context.Context, projectName string, r io.Reader, options VolumesRestoreOptions) error {
+	if s.VolumesRestoreFn == nil {
+		return ErrNotImplemented
+	}
+	return s.VolumesRestoreFn(ctx, projectName, r, options)
+}
+
+// VolumesList implements Service interface
+func (s *ServiceProxy) VolumesList(ctx context.Context, projectName string, options ResourcesListOptions) ([]ResourceSummary, error) {
+	if s.VolumesListFn == nil {
+		return nil, ErrNotImplemented
+	}
+	return s.VolumesListFn(ctx, projectName, options)
+}
+
+// VolumesPrune implements Service interface
+func (s *ServiceProxy) VolumesPrune(ctx context.Context, project *types.Project, options VolumesPruneOptions) error {
+	if s.VolumesPruneFn == nil {
+		return ErrNotImplemented
+	}
+	if err := s.intercept(ctx, "volumes prune", project); err != nil {
+		return err
+	}
+	return s.VolumesPruneFn(ctx, project, options)
+}
+
+// NetworksList implements Service interface
+func (s *ServiceProxy) NetworksList(ctx context.Context, projectName string, options ResourcesListOptions) ([]ResourceSummary, error) {
+	if s.NetworksListFn == nil {
+		return nil, ErrNotImplemented
+	}
+	return s.NetworksListFn(ctx, projectName, options)
+}
+
+// Supervise implements Service interface
+func (s *ServiceProxy) Supervise(ctx context.Context, project *types.Project, options SuperviseOptions) error {
+	if s.SuperviseFn == nil {
+		return ErrNotImplemented
+	}
+	if err := s.intercept(ctx, "supervise", project); err != nil {
+		return err
+	}
+	return s.SuperviseFn(ctx, project, options)
+}
+
+// Lint implements Service interface
+func (s *ServiceProxy) Lint(ctx context.Context, project *types.Project, options LintOptions) ([]LintFinding, error) {
+	if s.LintFn == nil {
+		return nil, ErrNotImplemented
+	}
+	if err := s.intercept(ctx, "lint", project); err != nil {
+		return nil, err
+	}
+	return s.LintFn(ctx, project, options)
+}
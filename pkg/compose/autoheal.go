@@ -0,0 +1,323 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+const (
+	// extAutoheal declares the policy to remediate service containers staying unhealthy
+	extAutoheal = "x-autoheal"
+
+	autohealRestart  = "restart"
+	autohealRecreate = "recreate"
+
+	eventHealthUnhealthy = "health_status: unhealthy"
+	eventHealthHealthy   = "health_status: healthy"
+)
+
+// autohealPolicy is the remediation applied to a container staying unhealthy longer than threshold
+type autohealPolicy struct {
+	action    string
+	threshold time.Duration
+}
+
+// getAutohealPolicy parses the x-autoheal extension of service. It can be set to true, to an action, or to a
+// mapping with `action` and `threshold` attributes
+func getAutohealPolicy(service types.ServiceConfig) (*autohealPolicy, error) {
+	declared, ok := service.Extensions[extAutoheal]
+	if !ok {
+		return nil, nil
+	}
+	policy := &autohealPolicy{action: autohealRestart}
+	switch value := declared.(type) {
+	case bool:
+		if !value {
+			return nil, nil
+		}
+	case string:
+		policy.action = value
+	case map[string]interface{}:
+		for key, attribute := range value {
+			switch key {
+			case "action":
+				policy.action = fmt.Sprint(attribute)
+			case "threshold":
+				threshold, err := time.ParseDuration(fmt.Sprint(attribute))
+				if err != nil {
+					return nil, errors.Wrapf(err, "service %q: invalid %s threshold", service.Name, extAutoheal)
+				}
+				policy.threshold = threshold
+			default:
+				return nil, fmt.Errorf("service %q: unsupported %s attribute %q", service.Name, extAutoheal, key)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("service %q: %s must be a boolean, an action or a mapping", service.Name, extAutoheal)
+	}
+	if policy.action != autohealRestart && policy.action != autohealRecreate {
+		return nil, fmt.Errorf("service %q: %s action must be one of %q or %q", service.Name, extAutoheal, autohealRestart, autohealRecreate)
+	}
+	return policy, nil
+}
+
+// autoheal restarts or recreates containers staying unhealthy longer than the threshold set by their service policy.
+// Actions are reported to listener as log lines
+type autoheal struct {
+	service  *composeService
+	project  *types.Project
+	policies map[string]autohealPolicy
+	listener api.ContainerEventListener
+	mutex    sync.Mutex
+	timers   map[string]*time.Timer
+	healing  map[string]bool
+}
+
+// newAutoheal returns nil when no service of project declares an autoheal policy
+func (s *composeService) newAutoheal(project *types.Project, listener api.ContainerEventListener, services ...string) (*autoheal, error) {
+	policies := map[string]autohealPolicy{}
+	for _, service := range project.Services {
+		if len(services) > 0 && !utils.StringContains(services, service.Name) {
+			continue
+		}
+		policy, err := getAutohealPolicy(service)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			policies[service.Name] = *policy
+		}
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	return &autoheal{
+		service:  s,
+		project:  project,
+		policies: policies,
+		listener: listener,
+		timers:   map[string]*time.Timer{},
+		healing:  map[string]bool{},
+	}, nil
+}
+
+func (a *autoheal) services() []string {
+	var services []string
+	for service := range a.policies {
+		services = append(services, service)
+	}
+	return services
+}
+
+// watch monitors health of containers until ctx is done
+func (a *autoheal) watch(ctx context.Context) error {
+	if err := a.scan(ctx); err != nil {
+		return err
+	}
+	err := a.service.Events(ctx, a.project.Name, api.EventsOptions{
+		Services: a.services(),
+		Consumer: func(event api.Event) error {
+			a.handle(ctx, event)
+			return nil
+		},
+	})
+	a.stop()
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// scan looks for containers already unhealthy, as engine only reports health status changes
+func (a *autoheal) scan(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	containers, err := a.service.getContainers(ctx, a.project.Name, oneOffExclude, false, a.services()...)
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		inspected, err := a.service.apiClient.ContainerInspect(ctx, container.ID)
+		if err != nil {
+			return err
+		}
+		if isUnhealthy(inspected) {
+			a.unhealthy(ctx, container.ID, container.Labels[api.ServiceLabel])
+		}
+	}
+	return nil
+}
+
+// handle processes a container event, scheduling a remediation when a container becomes unhealthy
+func (a *autoheal) handle(ctx context.Context, event api.Event) {
+	if a == nil {
+		return
+	}
+	if _, ok := a.policies[event.Service]; !ok {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	switch event.Status {
+	case eventHealthUnhealthy:
+		a.schedule(ctx, event.Container, event.Service)
+	case eventHealthHealthy, "die":
+		a.cancel(event.Container)
+	case "start", "destroy":
+		// restarted or replaced container is back
+		a.cancel(event.Container)
+		delete(a.healing, event.Container)
+	}
+}
+
+// isHealing tells if container is being restarted or replaced by autoheal
+func (a *autoheal) isHealing(container string) bool {
+	if a == nil {
+		return false
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.healing[container]
+}
+
+// stop cancels all pending remediations
+func (a *autoheal) stop() {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for container := range a.timers {
+		a.cancel(container)
+	}
+}
+
+func (a *autoheal) unhealthy(ctx context.Context, container, service string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.schedule(ctx, container, service)
+}
+
+func (a *autoheal) schedule(ctx context.Context, container, service string) {
+	if _, ok := a.timers[container]; ok || a.healing[container] {
+		return
+	}
+	policy := a.policies[service]
+	a.timers[container] = time.AfterFunc(policy.threshold, func() {
+		a.heal(ctx, container, service, policy)
+	})
+}
+
+func (a *autoheal) cancel(container string) {
+	if timer, ok := a.timers[container]; ok {
+		timer.Stop()
+		delete(a.timers, container)
+	}
+}
+
+func (a *autoheal) heal(ctx context.Context, id, service string, policy autohealPolicy) {
+	a.mutex.Lock()
+	if _, ok := a.timers[id]; !ok {
+		// remediation has been cancelled meanwhile
+		a.mutex.Unlock()
+		return
+	}
+	delete(a.timers, id)
+	a.healing[id] = true
+	a.mutex.Unlock()
+
+	inspected, err := a.service.apiClient.ContainerInspect(ctx, id)
+	if err != nil || !isUnhealthy(inspected) {
+		a.healed(id)
+		return
+	}
+	container := moby.Container{
+		ID:     inspected.ID,
+		Names:  []string{inspected.Name},
+		Labels: inspected.Config.Labels,
+	}
+	name := getContainerNameWithoutProject(container)
+	a.log(name, service, fmt.Sprintf("unhealthy for more than %s, applying %s", policy.threshold, policy.action))
+
+	switch policy.action {
+	case autohealRestart:
+		err = a.service.apiClient.ContainerRestart(ctx, id, nil)
+	case autohealRecreate:
+		err = a.recreate(ctx, service, container)
+	}
+	if err != nil {
+		a.healed(id)
+		a.log(name, service, fmt.Sprintf("failed to %s unhealthy container: %s", policy.action, err.Error()))
+	}
+}
+
+func (a *autoheal) recreate(ctx context.Context, name string, container moby.Container) error {
+	service, err := a.project.GetService(name)
+	if err != nil {
+		return err
+	}
+	created, err := a.service.recreateContainer(ctx, a.project, service, container, true, nil)
+	if err != nil {
+		return err
+	}
+	return a.service.startContainer(ctx, created)
+}
+
+func (a *autoheal) healed(container string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.healing, container)
+}
+
+func (a *autoheal) log(container, service, message string) {
+	a.listener(api.ContainerEvent{
+		Type:      api.ContainerEventLog,
+		Container: container,
+		Service:   service,
+		Line:      "autoheal: " + message,
+	})
+}
+
+func isUnhealthy(container moby.ContainerJSON) bool {
+	return container.State != nil && container.State.Running &&
+		container.State.Health != nil && container.State.Health.Status == moby.Unhealthy
+}
+
+func (s *composeService) Supervise(ctx context.Context, project *types.Project, options api.SuperviseOptions) error {
+	healer, err := s.newAutoheal(project, func(event api.ContainerEvent) {
+		options.Consumer.Log(event.Container, event.Service, event.Line)
+	}, options.Services...)
+	if err != nil {
+		return err
+	}
+	if healer == nil {
+		return fmt.Errorf("no service declares an %s policy", extAutoheal)
+	}
+	return healer.watch(ctx)
+}
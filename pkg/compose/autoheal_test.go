@@ -0,0 +1,155 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestGetAutohealPolicy(t *testing.T) {
+	policy, err := getAutohealPolicy(types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	assert.Assert(t, policy == nil)
+
+	policy, err = getAutohealPolicy(types.ServiceConfig{
+		Name:       "web",
+		Extensions: map[string]interface{}{extAutoheal: true},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, *policy, autohealPolicy{action: autohealRestart})
+
+	policy, err = getAutohealPolicy(types.ServiceConfig{
+		Name:       "web",
+		Extensions: map[string]interface{}{extAutoheal: false},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, policy == nil)
+
+	policy, err = getAutohealPolicy(types.ServiceConfig{
+		Name: "web",
+		Extensions: map[string]interface{}{extAutoheal: map[string]interface{}{
+			"action":    "recreate",
+			"threshold": "30s",
+		}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, *policy, autohealPolicy{action: autohealRecreate, threshold: 30 * time.Second})
+
+	_, err = getAutohealPolicy(types.ServiceConfig{
+		Name:       "web",
+		Extensions: map[string]interface{}{extAutoheal: "reboot"},
+	})
+	assert.Error(t, err, `service "web": x-autoheal action must be one of "restart" or "recreate"`)
+
+	_, err = getAutohealPolicy(types.ServiceConfig{
+		Name:       "web",
+		Extensions: map[string]interface{}{extAutoheal: map[string]interface{}{"threshold": "soon"}},
+	})
+	assert.ErrorContains(t, err, `service "web": invalid x-autoheal threshold`)
+}
+
+func TestAutohealRestartsUnhealthyContainer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			{Name: "web", Extensions: map[string]interface{}{extAutoheal: "restart"}},
+			{Name: "db"},
+		},
+	}
+	lines := make(chan string, 1)
+	healer, err := tested.newAutoheal(project, func(event compose.ContainerEvent) {
+		lines <- event.Container + " " + event.Line
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, healer.services(), []string{"web"})
+
+	api.EXPECT().ContainerInspect(gomock.Any(), "123").Return(moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{
+			ID:   "123",
+			Name: "/web-1",
+			State: &moby.ContainerState{
+				Running: true,
+				Health:  &moby.Health{Status: moby.Unhealthy},
+			},
+		},
+		Config: &container.Config{Labels: map[string]string{compose.ServiceLabel: "web"}},
+	}, nil)
+	restarted := make(chan struct{})
+	api.EXPECT().ContainerRestart(gomock.Any(), "123", nil).DoAndReturn(func(context.Context, string, *time.Duration) error {
+		close(restarted)
+		return nil
+	})
+
+	ctx := context.Background()
+	// events of services without a policy are ignored
+	healer.handle(ctx, compose.Event{Container: "456", Service: "db", Status: eventHealthUnhealthy})
+	healer.handle(ctx, compose.Event{Container: "123", Service: "web", Status: eventHealthUnhealthy})
+
+	assert.Equal(t, <-lines, "web-1 autoheal: unhealthy for more than 0s, applying restart")
+	<-restarted
+	assert.Assert(t, healer.isHealing("123"))
+
+	healer.handle(ctx, compose.Event{Container: "123", Service: "web", Status: "start"})
+	assert.Assert(t, !healer.isHealing("123"))
+}
+
+func TestAutohealCancelledOnceHealthy(t *testing.T) {
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			{Name: "web", Extensions: map[string]interface{}{extAutoheal: map[string]interface{}{"threshold": "1h"}}},
+		},
+	}
+	healer, err := tested.newAutoheal(project, func(event compose.ContainerEvent) {})
+	assert.NilError(t, err)
+
+	ctx := context.Background()
+	healer.handle(ctx, compose.Event{Container: "123", Service: "web", Status: eventHealthUnhealthy})
+	assert.Equal(t, len(healer.timers), 1)
+	healer.handle(ctx, compose.Event{Container: "123", Service: "web", Status: eventHealthHealthy})
+	assert.Equal(t, len(healer.timers), 0)
+}
+
+func TestNewAutohealWithoutPolicy(t *testing.T) {
+	project := &types.Project{
+		Name:     strings.ToLower(testProject),
+		Services: types.Services{{Name: "web"}},
+	}
+	healer, err := tested.newAutoheal(project, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, healer == nil)
+	// a missing healer is a no-op
+	healer.handle(context.Background(), compose.Event{Container: "123", Service: "web", Status: eventHealthUnhealthy})
+	assert.Assert(t, !healer.isHealing("123"))
+}
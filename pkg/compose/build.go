@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 	"github.com/containerd/containerd/platforms"
@@ -193,7 +194,7 @@ func (s *composeService) getLocalImagesDigests(ctx context.Context, project *typ
 }
 
 func (s *composeService) serverInfo(ctx context.Context) (command.ServerInfo, error) {
-	ping, err := s.apiClient.Ping(ctx)
+	ping, err := s.ping(ctx)
 	if err != nil {
 		return command.ServerInfo{}, err
 	}
@@ -213,6 +214,14 @@ func (s *composeService) doBuild(ctx context.Context, project *types.Project, op
 	if err != nil {
 		return nil, err
 	}
+	// images are built in parallel, each one is reported with the duration of the whole build
+	start := time.Now()
+	defer func() {
+		end := time.Now()
+		for image := range opts {
+			progress.ContextTimings(ctx).Add(image, buildStep, start, end)
+		}
+	}()
 	if buildkitEnabled, err := command.BuildKitEnabled(serverInfo); err != nil || !buildkitEnabled {
 		return s.doBuildClassic(ctx, opts)
 	}
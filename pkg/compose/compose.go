@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/docker/compose/v2/pkg/api"
 
@@ -36,16 +37,58 @@ import (
 var Separator = "-"
 
 // NewComposeService create a local implementation of the compose.Service API
-func NewComposeService(apiClient client.APIClient, configFile *configfile.ConfigFile) api.Service {
-	return &composeService{
+func NewComposeService(apiClient client.APIClient, configFile *configfile.ConfigFile, options ...Option) api.Service {
+	s := &composeService{
 		apiClient:  apiClient,
 		configFile: configFile,
+		pingCache:  &pingCache{},
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Option configures optional features of the compose.Service implementation
+type Option func(s *composeService)
+
+// WithSecretProvider configures the SecretProvider used to resolve external secrets
+func WithSecretProvider(provider api.SecretProvider) Option {
+	return func(s *composeService) {
+		s.secretProvider = provider
 	}
 }
 
 type composeService struct {
-	apiClient  client.APIClient
-	configFile *configfile.ConfigFile
+	apiClient      client.APIClient
+	configFile     *configfile.ConfigFile
+	secretProvider api.SecretProvider
+	pingCache      *pingCache
+}
+
+// pingCache holds the engine ping result, which doesn't change while a command runs
+type pingCache struct {
+	mutex sync.Mutex
+	ping  *moby.Ping
+}
+
+// ping queries the engine, only until it succeeds when the ping cache is set
+func (s *composeService) ping(ctx context.Context) (moby.Ping, error) {
+	if s.pingCache == nil {
+		return s.apiClient.Ping(ctx)
+	}
+	s.pingCache.mutex.Lock()
+	defer s.pingCache.mutex.Unlock()
+	if s.pingCache.ping != nil {
+		return *s.pingCache.ping, nil
+	}
+	ping, err := s.apiClient.Ping(ctx)
+	if err != nil {
+		// errors, like a cancelled context, are not cached
+		return ping, err
+	}
+	s.pingCache.ping = &ping
+	return ping, nil
 }
 
 func getCanonicalContainerName(c moby.Container) string {
@@ -29,6 +29,7 @@ import (
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/versions"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -41,6 +42,9 @@ import (
 const (
 	extLifecycle  = "x-lifecycle"
 	forceRecreate = "force_recreate"
+	// extDependsOnRestart lists the dependencies a service must be restarted with, as the compose file
+	// schema doesn't accept a `restart` attribute in `depends_on` (yet)
+	extDependsOnRestart = "x-depends-on-restart"
 
 	doubledContainerNameWarning = "WARNING: The %q service is using the custom container name %q. " +
 		"Docker requires each container to have a unique name. " +
@@ -56,6 +60,8 @@ type convergence struct {
 	service       *composeService
 	observedState map[string]Containers
 	stateMutex    sync.Mutex
+	// stoppedDisabled lists the disabled services which containers got stopped to be restarted with a dependency
+	stoppedDisabled []string
 }
 
 func (c *convergence) getObservedState(serviceName string) Containers {
@@ -86,7 +92,7 @@ func newConvergence(services []string, state Containers, s *composeService) *con
 }
 
 func (c *convergence) apply(ctx context.Context, project *types.Project, options api.CreateOptions) error {
-	return InDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+	err := InDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
 		service, err := project.GetService(name)
 		if err != nil {
 			return err
@@ -104,6 +110,28 @@ func (c *convergence) apply(ctx context.Context, project *types.Project, options
 		c.updateProject(project, name)
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	// disabled services stopped with a recreated dependency are started again along with the project
+	enableServices(project, c.stoppedDisabled)
+	return nil
+}
+
+// enableServices moves the named disabled services back to the project services
+func enableServices(project *types.Project, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	var disabled types.Services
+	for _, service := range project.DisabledServices {
+		if utils.StringContains(names, service.Name) {
+			project.Services = append(project.Services, service)
+		} else {
+			disabled = append(disabled, service)
+		}
+	}
+	project.DisabledServices = disabled
 }
 
 var mu sync.Mutex
@@ -173,6 +201,15 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 	actual := len(containers)
 	updated := make(Containers, expected)
 
+	dependentsStopped := false
+	stopDependents := func() error {
+		if dependentsStopped {
+			return nil
+		}
+		dependentsStopped = true
+		return c.stopDependentContainers(ctx, project, service.Name, timeout)
+	}
+
 	eg, _ := errgroup.WithContext(ctx)
 
 	for i, container := range containers {
@@ -180,6 +217,9 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 			// Scale Down
 			container := container
 			eg.Go(func() error {
+				if err := c.service.runPreStopHooks(ctx, progress.ContextWriter(ctx), container); err != nil {
+					return err
+				}
 				err := c.service.apiClient.ContainerStop(ctx, container.ID, timeout)
 				if err != nil {
 					return err
@@ -200,6 +240,9 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		name := getContainerProgressName(container)
 		diverged := container.Labels[api.ConfigHashLabel] != configHash
 		if diverged || recreate == api.RecreateForce || service.Extensions[extLifecycle] == forceRecreate {
+			if err := stopDependents(); err != nil {
+				return err
+			}
 			i, container := i, container
 			eg.Go(func() error {
 				recreated, err := c.service.recreateContainer(ctx, project, service, container, inherit, timeout)
@@ -219,6 +262,9 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		case ContainerExited:
 			w.Event(progress.CreatedEvent(name))
 		default:
+			if err := stopDependents(); err != nil {
+				return err
+			}
 			container := container
 			eg.Go(func() error {
 				return c.service.startContainer(ctx, container)
@@ -277,8 +323,8 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 	w := progress.ContextWriter(ctx)
 	for dep, config := range dependencies {
 		if config.Condition == types.ServiceConditionStarted {
-			// already managed by InDependencyOrder
-			return nil
+			// already managed by InDependencyOrder, other dependencies may still have to be waited for
+			continue
 		}
 
 		containers, err := s.getContainers(ctx, project.Name, oneOffExclude, false, dep)
@@ -292,7 +338,11 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 			ticker := time.NewTicker(500 * time.Millisecond)
 			defer ticker.Stop()
 			for {
-				<-ticker.C
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-ticker.C:
+				}
 				switch config.Condition {
 				case ServiceConditionRunningOrHealthy:
 					healthy, err := s.isServiceHealthy(ctx, project, dep, true)
@@ -379,6 +429,9 @@ func (s *composeService) recreateContainer(ctx context.Context, project *types.P
 	replaced moby.Container, inherit bool, timeout *time.Duration) (moby.Container, error) {
 	var created moby.Container
 	w := progress.ContextWriter(ctx)
+	if err := s.runPreStopHooks(ctx, w, replaced); err != nil {
+		return created, err
+	}
 	w.Event(progress.NewEvent(getContainerProgressName(replaced), progress.Working, "Recreate"))
 	err := s.apiClient.ContainerStop(ctx, replaced.ID, timeout)
 	if err != nil {
@@ -426,6 +479,64 @@ func setDependentLifecycle(project *types.Project, service string, strategy stri
 	}
 }
 
+// stopDependentContainers stops the containers of services to be restarted with the specified service, so they
+// get started again, in dependency order, once the service meets the expected condition
+func (c *convergence) stopDependentContainers(ctx context.Context, project *types.Project, service string, timeout *time.Duration) error {
+	dependents := getRestartDependents(project, service)
+	if len(dependents) == 0 {
+		return nil
+	}
+	containers, err := c.service.getContainers(ctx, project.Name, oneOffExclude, false, dependents...)
+	if err != nil {
+		return err
+	}
+	c.stateMutex.Lock()
+	for _, dependent := range dependents {
+		if _, err := project.GetService(dependent); err != nil && !utils.StringContains(c.stoppedDisabled, dependent) {
+			c.stoppedDisabled = append(c.stoppedDisabled, dependent)
+		}
+	}
+	c.stateMutex.Unlock()
+	return c.service.stopContainers(ctx, progress.ContextWriter(ctx), containers, timeout)
+}
+
+// getRestartDependents returns the services to be restarted when the specified service is recreated or restarted,
+// including the ones to be restarted with those
+func getRestartDependents(project *types.Project, service string) []string {
+	var dependents []string
+	// dependents are restarted even when the project has been restricted to the selected services
+	for _, s := range project.AllServices() {
+		if !restartsWith(s, service) || utils.StringContains(dependents, s.Name) {
+			continue
+		}
+		dependents = append(dependents, s.Name)
+		for _, d := range getRestartDependents(project, s.Name) {
+			if !utils.StringContains(dependents, d) {
+				dependents = append(dependents, d)
+			}
+		}
+	}
+	return dependents
+}
+
+// restartsWith tells if service declares it must be restarted with dependency
+func restartsWith(service types.ServiceConfig, dependency string) bool {
+	if _, ok := service.DependsOn[dependency]; !ok {
+		return false
+	}
+	switch restart := service.Extensions[extDependsOnRestart].(type) {
+	case []interface{}:
+		for _, d := range restart {
+			if d == dependency {
+				return true
+			}
+		}
+	case []string:
+		return utils.StringContains(restart, dependency)
+	}
+	return false
+}
+
 func (s *composeService) startContainer(ctx context.Context, container moby.Container) error {
 	w := progress.ContextWriter(ctx)
 	w.Event(progress.NewEvent(getContainerProgressName(container), progress.Working, "Restart"))
@@ -453,10 +564,35 @@ func (s *composeService) createMobyContainer(ctx context.Context, project *types
 		}
 		plat = &p
 	}
+	injections, err := s.getFileObjectInjections(ctx, *project, service)
+	if err != nil {
+		return created, err
+	}
+	links, err := s.getLinks(ctx, project.Name, service, number)
+	if err != nil {
+		return created, err
+	}
+	allNetworks, err := s.supportsMultipleEndpoints(ctx)
+	if err != nil {
+		return created, err
+	}
+	if allNetworks {
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{},
+		}
+		for _, netName := range service.NetworksByPriority() {
+			aliases := getNetworkAliases(project.Name, service, number, netName, useNetworkAliases)
+			networkingConfig.EndpointsConfig[project.Networks[netName].Name] = getEndpointSettings(service.Networks[netName], links, aliases...)
+		}
+	}
 	response, err := s.apiClient.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, plat, name)
 	if err != nil {
 		return created, err
 	}
+	err = s.injectFileObjects(ctx, response.ID, injections)
+	if err != nil {
+		return created, err
+	}
 	inspectedContainer, err := s.apiClient.ContainerInspect(ctx, response.ID)
 	if err != nil {
 		return created, err
@@ -469,20 +605,14 @@ func (s *composeService) createMobyContainer(ctx context.Context, project *types
 			Networks: inspectedContainer.NetworkSettings.Networks,
 		},
 	}
-	links, err := s.getLinks(ctx, project.Name, service, number)
-	if err != nil {
-		return created, err
+	if allNetworks {
+		// all endpoints have been configured by ContainerCreate
+		return created, nil
 	}
 	for _, netName := range service.NetworksByPriority() {
 		netwrk := project.Networks[netName]
 		cfg := service.Networks[netName]
-		aliases := []string{getContainerName(project.Name, service, number)}
-		if useNetworkAliases {
-			aliases = append(aliases, service.Name)
-			if cfg != nil {
-				aliases = append(aliases, cfg.Aliases...)
-			}
-		}
+		aliases := getNetworkAliases(project.Name, service, number, netName, useNetworkAliases)
 		if val, ok := created.NetworkSettings.Networks[netwrk.Name]; ok {
 			if shortIDAliasExists(created.ID, val.Aliases...) {
 				continue
@@ -500,6 +630,36 @@ func (s *composeService) createMobyContainer(ctx context.Context, project *types
 	return created, err
 }
 
+// apiVersionMultipleEndpoints is the first engine API version to configure all network endpoints on container creation
+const apiVersionMultipleEndpoints = "1.44"
+
+// supportsMultipleEndpoints tells if ContainerCreate attaches containers to all their networks.
+// Older engines only honor a single endpoint, other networks are then connected once the container is created.
+func (s *composeService) supportsMultipleEndpoints(ctx context.Context) (bool, error) {
+	ping, err := s.ping(ctx)
+	if err != nil {
+		return false, err
+	}
+	// requests are sent with the client version, which may be lower than the one the engine supports
+	version := s.apiClient.ClientVersion()
+	if versions.LessThan(ping.APIVersion, version) {
+		version = ping.APIVersion
+	}
+	return versions.GreaterThanOrEqualTo(version, apiVersionMultipleEndpoints), nil
+}
+
+// getNetworkAliases returns the aliases of a service container on network netName
+func getNetworkAliases(projectName string, service types.ServiceConfig, number int, netName string, useNetworkAliases bool) []string {
+	aliases := []string{getContainerName(projectName, service, number)}
+	if useNetworkAliases {
+		aliases = append(aliases, service.Name)
+		if cfg := service.Networks[netName]; cfg != nil {
+			aliases = append(aliases, cfg.Aliases...)
+		}
+	}
+	return aliases
+}
+
 // getLinks mimics V1 compose/service.py::Service::_get_links()
 func (s composeService) getLinks(ctx context.Context, projectName string, service types.ServiceConfig, number int) ([]string, error) {
 	var links []string
@@ -568,6 +728,15 @@ func shortIDAliasExists(containerID string, aliases ...string) bool {
 }
 
 func (s *composeService) connectContainerToNetwork(ctx context.Context, id string, netwrk string, cfg *types.ServiceNetworkConfig, links []string, aliases ...string) error {
+	err := s.apiClient.NetworkConnect(ctx, netwrk, id, getEndpointSettings(cfg, links, aliases...))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getEndpointSettings configures a container endpoint on a network, with static addresses set by the service
+func getEndpointSettings(cfg *types.ServiceNetworkConfig, links []string, aliases ...string) *network.EndpointSettings {
 	var (
 		ipv4Address string
 		ipv6Address string
@@ -581,17 +750,13 @@ func (s *composeService) connectContainerToNetwork(ctx context.Context, id strin
 			IPv6Address: ipv6Address,
 		}
 	}
-	err := s.apiClient.NetworkConnect(ctx, netwrk, id, &network.EndpointSettings{
+	return &network.EndpointSettings{
 		Aliases:           aliases,
 		IPAddress:         ipv4Address,
 		GlobalIPv6Address: ipv6Address,
 		Links:             links,
 		IPAMConfig:        ipam,
-	})
-	if err != nil {
-		return err
 	}
-	return nil
 }
 
 func (s *composeService) isServiceHealthy(ctx context.Context, project *types.Project, service string, fallbackRunning bool) (bool, error) {
@@ -647,7 +812,7 @@ func (s *composeService) isServiceCompleted(ctx context.Context, project *types.
 	return false, 0, nil
 }
 
-func (s *composeService) startService(ctx context.Context, project *types.Project, service types.ServiceConfig) error {
+func (s *composeService) startService(ctx context.Context, project *types.Project, service types.ServiceConfig, listener api.ContainerEventListener) error {
 	if service.Deploy != nil && service.Deploy.Replicas != nil && *service.Deploy.Replicas == 0 {
 		return nil
 	}
@@ -675,6 +840,11 @@ func (s *composeService) startService(ctx context.Context, project *types.Projec
 		return fmt.Errorf("service %q has no container to start", service.Name)
 	}
 
+	hooks, err := getServiceHooks(service, extPostStart)
+	if err != nil {
+		return err
+	}
+
 	w := progress.ContextWriter(ctx)
 	eg, ctx := errgroup.WithContext(ctx)
 	for _, container := range containers {
@@ -686,10 +856,18 @@ func (s *composeService) startService(ctx context.Context, project *types.Projec
 			eventName := getContainerProgressName(container)
 			w.Event(progress.StartingEvent(eventName))
 			err := s.apiClient.ContainerStart(ctx, container.ID, moby.ContainerStartOptions{})
-			if err == nil {
-				w.Event(progress.StartedEvent(eventName))
+			if err != nil {
+				return err
 			}
-			return err
+			if len(hooks) > 0 {
+				w.Event(progress.NewEvent(eventName, progress.Working, "Running post_start hooks"))
+				if err := s.runHooks(ctx, container, hooks, hookPostStart, listener); err != nil {
+					w.Event(progress.ErrorMessageEvent(eventName, "Error while running post_start hooks"))
+					return err
+				}
+			}
+			w.Event(progress.StartedEvent(eventName))
+			return nil
 		})
 	}
 	return eg.Wait()
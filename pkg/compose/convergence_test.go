@@ -19,13 +19,17 @@ package compose
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/mocks"
 	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	"github.com/golang/mock/gomock"
 	"gotest.tools/assert"
 )
@@ -184,3 +188,154 @@ func TestServiceLinks(t *testing.T) {
 		assert.Equal(t, links[2], "testProject-web-1:testProject-web-1")
 	})
 }
+
+func TestCreateMobyContainerAllNetworks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient, configFile: &configfile.ConfigFile{}, pingCache: &pingCache{}}
+
+	project := &types.Project{
+		Name: testProject,
+		Networks: types.Networks{
+			"front": {Name: testProject + "_front"},
+			"back":  {Name: testProject + "_back"},
+		},
+	}
+	service := types.ServiceConfig{
+		Name:  "web",
+		Image: "nginx",
+		Networks: map[string]*types.ServiceNetworkConfig{
+			"front": {Priority: 10, Ipv4Address: "10.5.0.2", Aliases: []string{"www"}},
+			"back":  {Priority: 1},
+		},
+	}
+
+	apiClient.EXPECT().DaemonHost().Return("").AnyTimes()
+	apiClient.EXPECT().ImageInspectWithRaw(gomock.Any(), "nginx").Return(moby.ImageInspect{Config: &container.Config{}}, nil, nil).Times(2)
+	// engine is only queried once
+	apiClient.EXPECT().Ping(gomock.Any()).Return(moby.Ping{APIVersion: "1.44"}, nil)
+	apiClient.EXPECT().ClientVersion().Return("1.44").AnyTimes()
+	apiClient.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *container.Config, _ *container.HostConfig, networking *network.NetworkingConfig, _ interface{}, _ string) (container.ContainerCreateCreatedBody, error) {
+			assert.Equal(t, len(networking.EndpointsConfig), 2)
+			front := networking.EndpointsConfig[testProject+"_front"]
+			assert.DeepEqual(t, front.Aliases[1:], []string{"web", "www"})
+			assert.Equal(t, front.IPAMConfig.IPv4Address, "10.5.0.2")
+			back := networking.EndpointsConfig[testProject+"_back"]
+			assert.DeepEqual(t, back.Aliases[1:], []string{"web"})
+			return container.ContainerCreateCreatedBody{ID: "123"}, nil
+		}).Times(2)
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{ID: "123", Name: "/web-1"},
+		Config:            &container.Config{},
+		NetworkSettings:   &moby.NetworkSettings{},
+	}, nil).Times(2)
+
+	for _, number := range []int{1, 2} {
+		_, err := tested.createMobyContainer(context.Background(), project, service, fmt.Sprintf("web-%d", number), number, nil, false, true, false)
+		assert.NilError(t, err)
+	}
+}
+
+func TestCreateMobyContainerLegacyNetworks(t *testing.T) {
+	// requests are sent with the lower of the client and engine versions
+	t.Run("legacy engine", func(t *testing.T) {
+		testCreateMobyContainerLegacyNetworks(t, "1.44", "1.41")
+	})
+	t.Run("legacy client", func(t *testing.T) {
+		testCreateMobyContainerLegacyNetworks(t, "1.41", "1.44")
+	})
+}
+
+func testCreateMobyContainerLegacyNetworks(t *testing.T, clientVersion, engineVersion string) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient, configFile: &configfile.ConfigFile{}}
+
+	project := &types.Project{
+		Name: testProject,
+		Networks: types.Networks{
+			"front": {Name: testProject + "_front"},
+			"back":  {Name: testProject + "_back"},
+		},
+	}
+	service := types.ServiceConfig{
+		Name:  "web",
+		Image: "nginx",
+		Networks: map[string]*types.ServiceNetworkConfig{
+			"front": {Priority: 10},
+			"back":  {Priority: 1, Ipv4Address: "10.6.0.2"},
+		},
+	}
+
+	apiClient.EXPECT().DaemonHost().Return("").AnyTimes()
+	apiClient.EXPECT().ImageInspectWithRaw(gomock.Any(), "nginx").Return(moby.ImageInspect{Config: &container.Config{}}, nil, nil)
+	apiClient.EXPECT().Ping(gomock.Any()).Return(moby.Ping{APIVersion: engineVersion}, nil)
+	apiClient.EXPECT().ClientVersion().Return(clientVersion).AnyTimes()
+	apiClient.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "web-1").
+		DoAndReturn(func(_ context.Context, _ *container.Config, _ *container.HostConfig, networking *network.NetworkingConfig, _ interface{}, _ string) (container.ContainerCreateCreatedBody, error) {
+			assert.Equal(t, len(networking.EndpointsConfig), 1)
+			assert.Assert(t, networking.EndpointsConfig[testProject+"_front"] != nil)
+			return container.ContainerCreateCreatedBody{ID: "0123456789abcdef"}, nil
+		})
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "0123456789abcdef").Return(moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{ID: "0123456789abcdef", Name: "/web-1"},
+		Config:            &container.Config{},
+		NetworkSettings: &moby.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+			testProject + "_front": {Aliases: []string{"0123456789ab"}},
+		}},
+	}, nil)
+	apiClient.EXPECT().NetworkConnect(gomock.Any(), testProject+"_back", "0123456789abcdef", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, endpoint *network.EndpointSettings) error {
+			assert.Equal(t, endpoint.IPAMConfig.IPv4Address, "10.6.0.2")
+			return nil
+		})
+
+	_, err := tested.createMobyContainer(context.Background(), project, service, "web-1", 1, nil, false, true, false)
+	assert.NilError(t, err)
+}
+
+func TestPingCacheIgnoresErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient, pingCache: &pingCache{}}
+
+	gomock.InOrder(
+		apiClient.EXPECT().Ping(gomock.Any()).Return(moby.Ping{}, context.Canceled),
+		apiClient.EXPECT().Ping(gomock.Any()).Return(moby.Ping{APIVersion: "1.44"}, nil),
+	)
+	_, err := tested.ping(context.Background())
+	assert.Equal(t, err, context.Canceled)
+	for i := 0; i < 2; i++ {
+		ping, err := tested.ping(context.Background())
+		assert.NilError(t, err)
+		assert.Equal(t, ping.APIVersion, "1.44")
+	}
+}
+
+func TestWaitDependenciesAfterStartedCondition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient}
+
+	db := testContainer("db", "123", false)
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{db}, nil).AnyTimes()
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{ID: "123", State: &moby.ContainerState{
+			Status: "running", Running: true, Health: &moby.Health{Status: moby.Unhealthy},
+		}},
+		Config: &container.Config{Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}}},
+	}, nil).AnyTimes()
+
+	// dependencies only required to be started don't end the wait for the other ones, whatever the map order
+	dependencies := types.DependsOnConfig{"db": {Condition: types.ServiceConditionHealthy}}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		dependencies[name] = types.ServiceDependency{Condition: types.ServiceConditionStarted}
+	}
+	err := tested.waitDependencies(context.Background(), &types.Project{Name: strings.ToLower(testProject)}, dependencies)
+	assert.Error(t, err, `container for service "db" is unhealthy`)
+}
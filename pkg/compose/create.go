@@ -63,6 +63,10 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
+	if err := s.checkResourceReservations(ctx, project, options.Services); err != nil {
+		return err
+	}
+
 	err = s.ensureImagesExists(ctx, project, options.QuietPull)
 	if err != nil {
 		return err
@@ -75,7 +79,7 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
-	if err := s.ensureNetworks(ctx, project.Networks); err != nil {
+	if err := s.ensureNetworks(ctx, project, options.RecreateNetworks); err != nil {
 		return err
 	}
 
@@ -195,9 +199,9 @@ func prepareServicesDependsOn(p *types.Project) error {
 	return nil
 }
 
-func (s *composeService) ensureNetworks(ctx context.Context, networks types.Networks) error {
-	for _, network := range networks {
-		err := s.ensureNetwork(ctx, network)
+func (s *composeService) ensureNetworks(ctx context.Context, project *types.Project, recreate bool) error {
+	for _, network := range project.Networks {
+		err := s.ensureNetwork(ctx, project, network, recreate)
 		if err != nil {
 			return err
 		}
@@ -291,32 +295,14 @@ func (s *composeService) getCreateOptions(ctx context.Context, p *types.Project,
 		service.NetworkMode = getDefaultNetworkMode(p, service)
 	}
 
+	// engines without support for multiple endpoints only honor the highest priority network
+	// see supportsMultipleEndpoints
 	var networkConfig *network.NetworkingConfig
-
 	for _, id := range service.NetworksByPriority() {
-		net := p.Networks[id]
 		config := service.Networks[id]
-		var ipam *network.EndpointIPAMConfig
-		var (
-			ipv4Address string
-			ipv6Address string
-		)
-		if config != nil {
-			ipv4Address = config.Ipv4Address
-			ipv6Address = config.Ipv6Address
-			ipam = &network.EndpointIPAMConfig{
-				IPv4Address: ipv4Address,
-				IPv6Address: ipv6Address,
-			}
-		}
 		networkConfig = &network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				net.Name: {
-					Aliases:     getAliases(service, config),
-					IPAddress:   ipv4Address,
-					IPv6Gateway: ipv6Address,
-					IPAMConfig:  ipam,
-				},
+				p.Networks[id].Name: getEndpointSettings(config, nil, getAliases(service, config)...),
 			},
 		}
 		break //nolint:staticcheck
@@ -441,6 +427,21 @@ func (s *composeService) prepareLabels(p *types.Project, service types.ServiceCo
 		dependencies = append(dependencies, s)
 	}
 	labels[api.DependenciesLabel] = strings.Join(dependencies, ",")
+
+	if _, err := getServiceHooks(service, extPostStart); err != nil {
+		return nil, err
+	}
+	preStop, err := getServiceHooks(service, extPreStop)
+	if err != nil {
+		return nil, err
+	}
+	if len(preStop) > 0 {
+		b, err := json.Marshal(preStop)
+		if err != nil {
+			return nil, err
+		}
+		labels[api.PreStopLabel] = string(b)
+	}
 	return labels, nil
 }
 
@@ -503,7 +504,7 @@ func getDeployResources(s types.ServiceConfig) container.Resources {
 		CPURealtimePeriod:  s.CPURTPeriod,
 		CPURealtimeRuntime: s.CPURTRuntime,
 		CPUShares:          s.CPUShares,
-		CPUPercent:         int64(s.CPUS * 100),
+		NanoCPUs:           int64(s.CPUS * 1e9),
 		CpusetCpus:         s.CPUSet,
 	}
 
@@ -514,8 +515,8 @@ func getDeployResources(s types.ServiceConfig) container.Resources {
 	setBlkio(s.BlkioConfig, &resources)
 
 	if s.Deploy != nil {
-		setLimits(s.Deploy.Resources.Limits, &resources)
-		setReservations(s.Deploy.Resources.Reservations, &resources)
+		setLimits(s, s.Deploy.Resources.Limits, &resources)
+		setReservations(s, s.Deploy.Resources.Reservations, &resources)
 	}
 
 	for _, device := range s.Devices {
@@ -562,10 +563,28 @@ func getDeployResources(s types.ServiceConfig) container.Resources {
 	return resources
 }
 
-func setReservations(reservations *types.Resource, resources *container.Resources) {
+// extPidsLimit is the `deploy.resources.limits` extension used to set a pids limit, as the compose
+// specification supported by compose-go does not (yet) declare one
+const extPidsLimit = "x-pids"
+
+func setReservations(s types.ServiceConfig, reservations *types.Resource, resources *container.Resources) {
 	if reservations == nil {
 		return
 	}
+	if reservations.MemoryBytes != 0 {
+		if s.MemReservation != 0 && s.MemReservation != reservations.MemoryBytes {
+			logrus.Warnf("service %q: both mem_reservation and deploy.resources.reservations.memory are set, using the latter", s.Name)
+		}
+		resources.MemoryReservation = int64(reservations.MemoryBytes)
+	}
+	if cpus, _ := parseCPUs(reservations.NanoCPUs); cpus != 0 {
+		// relative shares would lower the priority of a container reserving less than a CPU, below the engine default
+		logrus.Warnf("service %q: deploy.resources.reservations.cpus has no equivalent for a standalone container, "+
+			"it is only checked against the engine capacity", s.Name)
+	}
+	if len(reservations.GenericResources) > 0 {
+		logrus.Warnf("service %q: deploy.resources.reservations.generic_resources is only supported by swarm and will be ignored", s.Name)
+	}
 	for _, device := range reservations.Devices {
 		resources.DeviceRequests = append(resources.DeviceRequests, container.DeviceRequest{
 			Capabilities: [][]string{device.Capabilities},
@@ -576,17 +595,104 @@ func setReservations(reservations *types.Resource, resources *container.Resource
 	}
 }
 
-func setLimits(limits *types.Resource, resources *container.Resources) {
+func setLimits(s types.ServiceConfig, limits *types.Resource, resources *container.Resources) {
 	if limits == nil {
 		return
 	}
 	if limits.MemoryBytes != 0 {
+		if s.MemLimit != 0 && s.MemLimit != limits.MemoryBytes {
+			logrus.Warnf("service %q: both mem_limit and deploy.resources.limits.memory are set, using the latter", s.Name)
+		}
 		resources.Memory = int64(limits.MemoryBytes)
 	}
-	if limits.NanoCPUs != "" {
-		i, _ := strconv.ParseInt(limits.NanoCPUs, 10, 64)
-		resources.NanoCPUs = i
+	if cpus, _ := parseCPUs(limits.NanoCPUs); cpus != 0 {
+		if s.CPUS != 0 && s.CPUS != cpus {
+			logrus.Warnf("service %q: both cpus and deploy.resources.limits.cpus are set, using the latter", s.Name)
+		}
+		resources.NanoCPUs = int64(cpus * 1e9)
+	}
+	if pids, ok := limits.Extensions[extPidsLimit]; ok {
+		limit, err := strconv.ParseInt(fmt.Sprint(pids), 10, 64)
+		if err != nil {
+			logrus.Warnf("service %q: invalid deploy.resources.limits.%s value %v", s.Name, extPidsLimit, pids)
+			return
+		}
+		if s.PidsLimit != 0 && s.PidsLimit != limit {
+			logrus.Warnf("service %q: both pids_limit and deploy.resources.limits.%s are set, using the latter", s.Name, extPidsLimit)
+		}
+		resources.PidsLimit = &limit
+	}
+}
+
+// parseCPUs parses a fractional number of CPUs as declared by `deploy.resources`
+func parseCPUs(cpus string) (float32, error) {
+	if cpus == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(cpus, 32)
+	return float32(f), err
+}
+
+func validateDeviceRequests(service types.ServiceConfig) error {
+	if service.Deploy == nil || service.Deploy.Resources.Reservations == nil {
+		return nil
+	}
+	for _, device := range service.Deploy.Resources.Reservations.Devices {
+		if len(device.Capabilities) == 0 {
+			return fmt.Errorf("service %q: device reservation requires capabilities to be set", service.Name)
+		}
+		if device.Count != 0 && len(device.IDs) > 0 {
+			return fmt.Errorf("service %q: device reservation can't set both count and device_ids", service.Name)
+		}
+	}
+	return nil
+}
+
+// checkResourceReservations fails early when the reservations declared by the selected services
+// can't be met by the engine, rather than leaving containers to compete for missing resources
+func (s *composeService) checkResourceReservations(ctx context.Context, project *types.Project, services []string) error {
+	var (
+		cpus   float64
+		memory int64
+	)
+	for _, service := range project.Services {
+		if !utils.StringContains(services, service.Name) {
+			continue
+		}
+		if err := validateDeviceRequests(service); err != nil {
+			return err
+		}
+		if service.Deploy == nil || service.Deploy.Resources.Reservations == nil {
+			continue
+		}
+		reservations := service.Deploy.Resources.Reservations
+		scale, err := getScale(service)
+		if err != nil {
+			return err
+		}
+		c, err := parseCPUs(reservations.NanoCPUs)
+		if err != nil {
+			return errors.Wrapf(err, "service %q: invalid cpus reservation", service.Name)
+		}
+		cpus += float64(c) * float64(scale)
+		memory += int64(reservations.MemoryBytes) * int64(scale)
+	}
+	if cpus == 0 && memory == 0 {
+		return nil
+	}
+
+	info, err := s.apiClient.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if cpus > float64(info.NCPU) {
+		return fmt.Errorf("services reserve %g CPUs but the engine only has %d", cpus, info.NCPU)
+	}
+	if info.MemTotal > 0 && memory > info.MemTotal {
+		return fmt.Errorf("services reserve %s of memory but the engine only has %s",
+			units.BytesSize(float64(memory)), units.BytesSize(float64(info.MemTotal)))
 	}
+	return nil
 }
 
 func setBlkio(blkio *types.BlkioConfig, resources *container.Resources) {
@@ -838,19 +944,17 @@ func fillBindMounts(p types.Project, s types.ServiceConfig, m map[string]mount.M
 func buildContainerConfigMounts(p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
 	var mounts = map[string]mount.Mount{}
 
-	configsBaseDir := "/"
 	for _, config := range s.Configs {
-		target := config.Target
-		if config.Target == "" {
-			target = configsBaseDir + config.Source
-		} else if !isUnixAbs(config.Target) {
-			target = configsBaseDir + config.Target
-		}
+		target := fileObjectTarget(types.FileReferenceConfig(config), configsDir)
 
 		definedConfig := p.Configs[config.Source]
 		if definedConfig.External.External {
 			return nil, fmt.Errorf("unsupported external config %s", definedConfig.Name)
 		}
+		if needsInjection(types.FileObjectConfig(definedConfig), types.FileReferenceConfig(config)) {
+			// copied into container by injectFileObjects
+			continue
+		}
 
 		bindMount, err := buildMount(p, types.ServiceVolumeConfig{
 			Type:     types.VolumeTypeBind,
@@ -873,18 +977,13 @@ func buildContainerConfigMounts(p types.Project, s types.ServiceConfig) ([]mount
 func buildContainerSecretMounts(p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
 	var mounts = map[string]mount.Mount{}
 
-	secretsDir := "/run/secrets/"
 	for _, secret := range s.Secrets {
-		target := secret.Target
-		if secret.Target == "" {
-			target = secretsDir + secret.Source
-		} else if !isUnixAbs(secret.Target) {
-			target = secretsDir + secret.Target
-		}
+		target := fileObjectTarget(types.FileReferenceConfig(secret), secretsDir)
 
 		definedSecret := p.Secrets[secret.Source]
-		if definedSecret.External.External {
-			return nil, fmt.Errorf("unsupported external secret %s", definedSecret.Name)
+		if definedSecret.External.External || needsInjection(types.FileObjectConfig(definedSecret), types.FileReferenceConfig(secret)) {
+			// copied into container by injectFileObjects
+			continue
 		}
 
 		mount, err := buildMount(p, types.ServiceVolumeConfig{
@@ -1015,8 +1114,8 @@ func getAliases(s types.ServiceConfig, c *types.ServiceNetworkConfig) []string {
 	return aliases
 }
 
-func (s *composeService) ensureNetwork(ctx context.Context, n types.NetworkConfig) error {
-	_, err := s.apiClient.NetworkInspect(ctx, n.Name, moby.NetworkInspectOptions{})
+func (s *composeService) ensureNetwork(ctx context.Context, project *types.Project, n types.NetworkConfig, recreate bool) error {
+	inspected, err := s.apiClient.NetworkInspect(ctx, n.Name, moby.NetworkInspectOptions{})
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			if n.External.External {
@@ -1030,59 +1129,63 @@ func (s *composeService) ensureNetwork(ctx context.Context, n types.NetworkConfi
 				}
 				return fmt.Errorf("network %s declared as external, but could not be found", n.Name)
 			}
-			var ipam *network.IPAM
-			if n.Ipam.Config != nil {
-				var config []network.IPAMConfig
-				for _, pool := range n.Ipam.Config {
-					config = append(config, network.IPAMConfig{
-						Subnet:     pool.Subnet,
-						IPRange:    pool.IPRange,
-						Gateway:    pool.Gateway,
-						AuxAddress: pool.AuxiliaryAddresses,
-					})
-				}
-				ipam = &network.IPAM{
-					Driver: n.Ipam.Driver,
-					Config: config,
-				}
-			}
-			createOpts := moby.NetworkCreate{
-				// TODO NameSpace Labels
-				Labels:     n.Labels,
-				Driver:     n.Driver,
-				Options:    n.DriverOpts,
-				Internal:   n.Internal,
-				Attachable: n.Attachable,
-				IPAM:       ipam,
-				EnableIPv6: n.EnableIPv6,
-			}
-
-			if n.Ipam.Driver != "" || len(n.Ipam.Config) > 0 {
-				createOpts.IPAM = &network.IPAM{}
-			}
+			return s.createNetwork(ctx, n)
+		}
+		return err
+	}
+	if n.External.External {
+		return nil
+	}
 
-			if n.Ipam.Driver != "" {
-				createOpts.IPAM.Driver = n.Ipam.Driver
-			}
+	drift := networkDrift(n, inspected)
+	if len(drift) == 0 {
+		return nil
+	}
+	if !recreate {
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.NewEvent(fmt.Sprintf("Network %s", n.Name), progress.Done,
+			fmt.Sprintf("Warning: configuration drift (%s), use --recreate-networks to apply", strings.Join(drift, ", "))))
+		return nil
+	}
+	return s.recreateNetwork(ctx, project, n, inspected)
+}
 
-			for _, ipamConfig := range n.Ipam.Config {
-				config := network.IPAMConfig{
-					Subnet: ipamConfig.Subnet,
-				}
-				createOpts.IPAM.Config = append(createOpts.IPAM.Config, config)
-			}
-			networkEventName := fmt.Sprintf("Network %s", n.Name)
-			w := progress.ContextWriter(ctx)
-			w.Event(progress.CreatingEvent(networkEventName))
-			if _, err := s.apiClient.NetworkCreate(ctx, n.Name, createOpts); err != nil {
-				w.Event(progress.ErrorEvent(networkEventName))
-				return errors.Wrapf(err, "failed to create network %s", n.Name)
-			}
-			w.Event(progress.CreatedEvent(networkEventName))
-			return nil
+func (s *composeService) createNetwork(ctx context.Context, n types.NetworkConfig) error {
+	var ipam *network.IPAM
+	if n.Ipam.Driver != "" || len(n.Ipam.Config) > 0 {
+		var config []network.IPAMConfig
+		for _, pool := range n.Ipam.Config {
+			config = append(config, network.IPAMConfig{
+				Subnet:     pool.Subnet,
+				IPRange:    pool.IPRange,
+				Gateway:    pool.Gateway,
+				AuxAddress: pool.AuxiliaryAddresses,
+			})
 		}
-		return err
+		ipam = &network.IPAM{
+			Driver: n.Ipam.Driver,
+			Config: config,
+		}
+	}
+	createOpts := moby.NetworkCreate{
+		// TODO NameSpace Labels
+		Labels:     n.Labels,
+		Driver:     n.Driver,
+		Options:    n.DriverOpts,
+		Internal:   n.Internal,
+		Attachable: n.Attachable,
+		IPAM:       ipam,
+		EnableIPv6: n.EnableIPv6,
+	}
+
+	networkEventName := fmt.Sprintf("Network %s", n.Name)
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.CreatingEvent(networkEventName))
+	if _, err := s.apiClient.NetworkCreate(ctx, n.Name, createOpts); err != nil {
+		w.Event(progress.ErrorEvent(networkEventName))
+		return errors.Wrapf(err, "failed to create network %s", n.Name)
 	}
+	w.Event(progress.CreatedEvent(networkEventName))
 	return nil
 }
 
@@ -1118,12 +1221,11 @@ func (s *composeService) ensureVolume(ctx context.Context, volume types.VolumeCo
 	}
 
 	// Volume exists with name, but let's double-check this is the expected one
-	p, ok := inspected.Labels[api.ProjectLabel]
-	if !ok {
-		logrus.Warnf("volume %q already exists but was not created by Docker Compose. Use `external: true` to use an existing volume", volume.Name)
-	}
-	if ok && p != project {
-		logrus.Warnf("volume %q already exists but was not created for project %q. Use `external: true` to use an existing volume", volume.Name, p)
+	drift := volumeDrift(volume, inspected, project)
+	if len(drift) > 0 {
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.NewEvent(fmt.Sprintf("Volume %q", volume.Name), progress.Done,
+			fmt.Sprintf("Warning: configuration drift (%s). Use `external: true` to use an existing volume", strings.Join(drift, ", "))))
 	}
 	return nil
 }
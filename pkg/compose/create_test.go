@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sort"
@@ -27,7 +28,10 @@ import (
 	"github.com/docker/compose/v2/pkg/api"
 	moby "github.com/docker/docker/api/types"
 	mountTypes "github.com/docker/docker/api/types/mount"
+	"github.com/golang/mock/gomock"
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/mocks"
 )
 
 func TestBuildBindMount(t *testing.T) {
@@ -222,3 +226,85 @@ func TestGetDefaultNetworkMode(t *testing.T) {
 		assert.Equal(t, getDefaultNetworkMode(&project, service), "none")
 	})
 }
+
+func TestGetDeployResources(t *testing.T) {
+	service := composetypes.ServiceConfig{
+		Name:      "myService",
+		MemLimit:  composetypes.UnitBytes(64),
+		CPUS:      2,
+		PidsLimit: 100,
+		Deploy: &composetypes.DeployConfig{
+			Resources: composetypes.Resources{
+				Limits: &composetypes.Resource{
+					NanoCPUs:    "0.5",
+					MemoryBytes: composetypes.UnitBytes(128),
+					Extensions:  map[string]interface{}{extPidsLimit: 50},
+				},
+				Reservations: &composetypes.Resource{
+					NanoCPUs:    "0.25",
+					MemoryBytes: composetypes.UnitBytes(32),
+					Devices: []composetypes.DeviceRequest{
+						{Capabilities: []string{"gpu"}, Count: 1, Driver: "nvidia"},
+					},
+				},
+			},
+		},
+	}
+
+	resources := getDeployResources(service)
+	assert.Equal(t, resources.NanoCPUs, int64(5e8))
+	assert.Equal(t, resources.Memory, int64(128))
+	assert.Equal(t, *resources.PidsLimit, int64(50))
+	assert.Equal(t, resources.MemoryReservation, int64(32))
+	assert.Equal(t, resources.CPUShares, int64(0))
+	assert.Equal(t, len(resources.DeviceRequests), 1)
+	assert.DeepEqual(t, resources.DeviceRequests[0].Capabilities, [][]string{{"gpu"}})
+
+	service.Deploy = nil
+	resources = getDeployResources(service)
+	assert.Equal(t, resources.NanoCPUs, int64(2e9))
+	assert.Equal(t, resources.Memory, int64(64))
+	assert.Equal(t, *resources.PidsLimit, int64(100))
+}
+
+func TestCheckResourceReservations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient}
+
+	replicas := uint64(2)
+	project := composetypes.Project{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name: "db",
+				Deploy: &composetypes.DeployConfig{
+					Replicas: &replicas,
+					Resources: composetypes.Resources{
+						Reservations: &composetypes.Resource{NanoCPUs: "1.5", MemoryBytes: composetypes.UnitBytes(1024)},
+					},
+				},
+			},
+			{Name: "web"},
+		},
+	}
+
+	assert.NilError(t, tested.checkResourceReservations(context.Background(), &project, []string{"web"}))
+
+	apiClient.EXPECT().Info(gomock.Any()).Return(moby.Info{NCPU: 4, MemTotal: 4096}, nil)
+	assert.NilError(t, tested.checkResourceReservations(context.Background(), &project, []string{"db", "web"}))
+
+	apiClient.EXPECT().Info(gomock.Any()).Return(moby.Info{NCPU: 2, MemTotal: 4096}, nil)
+	err := tested.checkResourceReservations(context.Background(), &project, []string{"db"})
+	assert.ErrorContains(t, err, "services reserve 3 CPUs but the engine only has 2")
+
+	apiClient.EXPECT().Info(gomock.Any()).Return(moby.Info{NCPU: 4, MemTotal: 1024}, nil)
+	err = tested.checkResourceReservations(context.Background(), &project, []string{"db"})
+	assert.ErrorContains(t, err, "of memory but the engine only has")
+
+	project.Services[0].Deploy.Resources.Reservations.Devices = []composetypes.DeviceRequest{
+		{Capabilities: []string{"gpu"}, Count: 1, IDs: []string{"0"}},
+	}
+	err = tested.checkResourceReservations(context.Background(), &project, []string{"db"})
+	assert.ErrorContains(t, err, "can't set both count and device_ids")
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/compose-spec/compose-go/types"
@@ -191,12 +192,20 @@ func (s *composeService) stopContainers(ctx context.Context, w progress.Writer,
 		container := container
 		eg.Go(func() error {
 			eventName := getContainerProgressName(container)
+			if err := s.runPreStopHooks(ctx, w, container); err != nil {
+				return err
+			}
 			w.Event(progress.StoppingEvent(eventName))
 			err := s.apiClient.ContainerStop(ctx, container.ID, timeout)
 			if err != nil {
 				w.Event(progress.ErrorMessageEvent(eventName, "Error while Stopping"))
 				return err
 			}
+			if gracePeriod, killed := s.killedOnStop(ctx, container, timeout); killed {
+				w.Event(progress.NewEvent(eventName, progress.Done, "Killed"))
+				w.TailMsgf("%s didn't stop within its %s grace period and was killed", eventName, gracePeriod)
+				return nil
+			}
 			w.Event(progress.StoppedEvent(eventName))
 			return nil
 		})
@@ -204,6 +213,52 @@ func (s *composeService) stopContainers(ctx context.Context, w progress.Writer,
 	return eg.Wait()
 }
 
+// killedOnStop tells if a stopped container had to be killed by engine, as it didn't stop within its grace period.
+// The grace period which applied is returned
+func (s *composeService) killedOnStop(ctx context.Context, container moby.Container, timeout *time.Duration) (time.Duration, bool) {
+	if container.State != ContainerRunning {
+		return 0, false
+	}
+	inspected, err := s.apiClient.ContainerInspect(ctx, container.ID)
+	if err != nil || inspected.State == nil || inspected.Config == nil {
+		// container might have been removed once stopped
+		return 0, false
+	}
+	if inspected.State.OOMKilled || inspected.State.ExitCode != 128+int(syscall.SIGKILL) {
+		return 0, false
+	}
+	switch strings.TrimPrefix(strings.ToUpper(inspected.Config.StopSignal), "SIG") {
+	case "KILL", "9":
+		// container was expected to be killed
+		return 0, false
+	}
+	gracePeriod := defaultStopTimeout
+	switch {
+	case timeout != nil:
+		gracePeriod = *timeout
+	case inspected.Config.StopTimeout != nil:
+		gracePeriod = time.Duration(*inspected.Config.StopTimeout) * time.Second
+	}
+	return gracePeriod, true
+}
+
+func (s *composeService) runPreStopHooks(ctx context.Context, w progress.Writer, container moby.Container) error {
+	if container.State != ContainerRunning {
+		return nil
+	}
+	hooks, err := getPreStopHooks(container)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+	eventName := getContainerProgressName(container)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Running pre_stop hooks"))
+	if err := s.runHooks(ctx, container, hooks, hookPreStop, nil); err != nil {
+		w.Event(progress.ErrorMessageEvent(eventName, "Error while running pre_stop hooks"))
+		return err
+	}
+	return nil
+}
+
 func (s *composeService) removeContainers(ctx context.Context, w progress.Writer, containers []moby.Container, timeout *time.Duration, volumes bool) error {
 	eg, _ := errgroup.WithContext(ctx)
 	for _, container := range containers {
@@ -0,0 +1,183 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+// networkDrift lists attributes of an existing network which differ from the compose model
+func networkDrift(n types.NetworkConfig, inspected moby.NetworkResource) []string {
+	var drift []string
+	if n.Driver != "" && n.Driver != inspected.Driver {
+		drift = append(drift, fmt.Sprintf("driver %q => %q", inspected.Driver, n.Driver))
+	}
+	drift = append(drift, optionsDrift(n.DriverOpts, inspected.Options)...)
+	if n.Internal != inspected.Internal {
+		drift = append(drift, fmt.Sprintf("internal %t => %t", inspected.Internal, n.Internal))
+	}
+	if n.Attachable != inspected.Attachable {
+		drift = append(drift, fmt.Sprintf("attachable %t => %t", inspected.Attachable, n.Attachable))
+	}
+	if n.EnableIPv6 != inspected.EnableIPv6 {
+		drift = append(drift, fmt.Sprintf("enable_ipv6 %t => %t", inspected.EnableIPv6, n.EnableIPv6))
+	}
+	if n.Ipam.Driver != "" && n.Ipam.Driver != inspected.IPAM.Driver {
+		drift = append(drift, fmt.Sprintf("ipam.driver %q => %q", inspected.IPAM.Driver, n.Ipam.Driver))
+	}
+	for _, pool := range n.Ipam.Config {
+		if !hasIPAMConfig(inspected.IPAM.Config, pool) {
+			drift = append(drift, fmt.Sprintf("ipam.config subnet %q", pool.Subnet))
+		}
+	}
+	return drift
+}
+
+func hasIPAMConfig(configs []network.IPAMConfig, pool *types.IPAMPool) bool {
+	for _, config := range configs {
+		if config.Subnet != pool.Subnet {
+			continue
+		}
+		return (pool.IPRange == "" || pool.IPRange == config.IPRange) &&
+			(pool.Gateway == "" || pool.Gateway == config.Gateway)
+	}
+	return false
+}
+
+// volumeDrift lists attributes of an existing volume which differ from the compose model
+func volumeDrift(volume types.VolumeConfig, inspected moby.Volume, project string) []string {
+	var drift []string
+	p, ok := inspected.Labels[api.ProjectLabel]
+	if !ok {
+		drift = append(drift, "not created by Docker Compose")
+	}
+	if ok && p != project {
+		drift = append(drift, fmt.Sprintf("created for project %q", p))
+	}
+	if volume.Driver != "" && volume.Driver != inspected.Driver {
+		drift = append(drift, fmt.Sprintf("driver %q => %q", inspected.Driver, volume.Driver))
+	}
+	return append(drift, optionsDrift(volume.DriverOpts, inspected.Options)...)
+}
+
+// optionsDrift compares driver options set by the compose model with actual ones
+func optionsDrift(expected map[string]string, actual map[string]string) []string {
+	var drift []string
+	for k, v := range expected {
+		if actual[k] != v {
+			drift = append(drift, fmt.Sprintf("driver_opts.%s %q => %q", k, actual[k], v))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// recreateNetwork removes and creates network again, disconnecting containers in reverse dependency order,
+// then connecting them back with the same endpoint settings in dependency order
+func (s *composeService) recreateNetwork(ctx context.Context, project *types.Project, n types.NetworkConfig, inspected moby.NetworkResource) error {
+	containers, err := s.getContainers(ctx, project.Name, oneOffInclude, true)
+	if err != nil {
+		return err
+	}
+	// containers of project services are reconnected in dependency order, others afterwards
+	var connected Containers
+	var others []string
+	services := project.ServiceNames()
+	for id := range inspected.Containers {
+		found := containers.filter(func(c moby.Container) bool { return c.ID == id }).filter(isService(services...))
+		if len(found) == 0 {
+			others = append(others, id)
+		}
+		connected = append(connected, found...)
+	}
+	sort.Strings(others)
+
+	endpoints := map[string]*network.EndpointSettings{}
+	for id := range inspected.Containers {
+		container, err := s.apiClient.ContainerInspect(ctx, id)
+		if err != nil {
+			return err
+		}
+		if endpoint, ok := container.NetworkSettings.Networks[n.Name]; ok {
+			endpoints[id] = &network.EndpointSettings{
+				Aliases:    endpoint.Aliases,
+				Links:      endpoint.Links,
+				IPAMConfig: endpoint.IPAMConfig,
+			}
+		}
+	}
+
+	for _, id := range others {
+		if err := s.apiClient.NetworkDisconnect(ctx, n.Name, id, true); err != nil {
+			return err
+		}
+	}
+	err = InReverseDependencyOrder(ctx, project, func(c context.Context, service string) error {
+		for _, container := range connected.filter(isService(service)) {
+			if err := s.apiClient.NetworkDisconnect(ctx, n.Name, container.ID, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.removeNetwork(ctx, inspected.ID, n.Name); err != nil {
+		return err
+	}
+	if err := s.createNetwork(ctx, n); err != nil {
+		return err
+	}
+
+	w := progress.ContextWriter(ctx)
+	connect := func(id string) error {
+		if err := s.apiClient.NetworkConnect(ctx, n.Name, id, endpoints[id]); err != nil {
+			return errors.Wrapf(err, "failed to reconnect container %s to network %s", id, n.Name)
+		}
+		return nil
+	}
+	err = InDependencyOrder(ctx, project, func(c context.Context, service string) error {
+		for _, container := range connected.filter(isService(service)) {
+			if err := connect(container.ID); err != nil {
+				return err
+			}
+			w.Event(progress.NewEvent(getContainerProgressName(container), progress.Done, fmt.Sprintf("Reconnected to %s", n.Name)))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range others {
+		if err := connect(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestNetworkDrift(t *testing.T) {
+	model := types.NetworkConfig{
+		Name:       "test_back",
+		DriverOpts: map[string]string{"com.docker.network.bridge.enable_icc": "false"},
+		Internal:   true,
+		Ipam: types.IPAMConfig{
+			Config: []*types.IPAMPool{{Subnet: "10.5.0.0/16", Gateway: "10.5.0.1"}},
+		},
+	}
+	inspected := moby.NetworkResource{
+		Name:    "test_back",
+		Driver:  "bridge",
+		Options: map[string]string{"com.docker.network.bridge.enable_icc": "false"},
+		IPAM: network.IPAM{
+			Driver: "default",
+			Config: []network.IPAMConfig{{Subnet: "10.5.0.0/16", Gateway: "10.5.0.1"}},
+		},
+		Internal: true,
+	}
+	assert.Equal(t, len(networkDrift(model, inspected)), 0)
+
+	inspected.Internal = false
+	inspected.Options = nil
+	inspected.IPAM.Config = []network.IPAMConfig{{Subnet: "172.20.0.0/16"}}
+	assert.DeepEqual(t, networkDrift(model, inspected), []string{
+		`driver_opts.com.docker.network.bridge.enable_icc "" => "false"`,
+		"internal false => true",
+		`ipam.config subnet "10.5.0.0/16"`,
+	})
+}
+
+func TestVolumeDrift(t *testing.T) {
+	model := types.VolumeConfig{Name: "test_data", Driver: "local", DriverOpts: map[string]string{"type": "tmpfs"}}
+	inspected := moby.Volume{
+		Name:    "test_data",
+		Driver:  "local",
+		Options: map[string]string{"type": "tmpfs"},
+		Labels:  map[string]string{compose.ProjectLabel: "test"},
+	}
+	assert.Equal(t, len(volumeDrift(model, inspected, "test")), 0)
+	assert.DeepEqual(t, volumeDrift(model, inspected, "other"), []string{`created for project "test"`})
+
+	inspected.Labels = nil
+	inspected.Options = map[string]string{"type": "nfs"}
+	assert.DeepEqual(t, volumeDrift(model, inspected, "test"), []string{
+		"not created by Docker Compose",
+		`driver_opts.type "nfs" => "tmpfs"`,
+	})
+}
+
+func TestRecreateNetwork(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	project := &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			{Name: "web", DependsOn: types.DependsOnConfig{"db": {}}},
+			{Name: "db"},
+		},
+	}
+	n := types.NetworkConfig{Name: "testproject_default", Internal: true}
+	inspected := moby.NetworkResource{
+		ID:   "net",
+		Name: "testproject_default",
+		Containers: map[string]moby.EndpointResource{
+			"db1":     {},
+			"web1":    {},
+			"foreign": {},
+		},
+	}
+	endpoint := func(alias string) moby.ContainerJSON {
+		return moby.ContainerJSON{NetworkSettings: &moby.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"testproject_default": {Aliases: []string{alias}, IPAddress: "172.20.0.2"},
+		}}}
+	}
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return([]moby.Container{
+		testContainer("db", "db1", false),
+		testContainer("web", "web1", false),
+	}, nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "db1").Return(endpoint("db"), nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "web1").Return(endpoint("web"), nil)
+	api.EXPECT().ContainerInspect(gomock.Any(), "foreign").Return(endpoint("foreign"), nil)
+	gomock.InOrder(
+		api.EXPECT().NetworkDisconnect(gomock.Any(), "testproject_default", "foreign", true).Return(nil),
+		api.EXPECT().NetworkDisconnect(gomock.Any(), "testproject_default", "web1", true).Return(nil),
+		api.EXPECT().NetworkDisconnect(gomock.Any(), "testproject_default", "db1", true).Return(nil),
+		api.EXPECT().NetworkRemove(gomock.Any(), "net").Return(nil),
+		api.EXPECT().NetworkCreate(gomock.Any(), "testproject_default", moby.NetworkCreate{Internal: true}).Return(moby.NetworkCreateResponse{}, nil),
+		api.EXPECT().NetworkConnect(gomock.Any(), "testproject_default", "db1", &network.EndpointSettings{Aliases: []string{"db"}}).Return(nil),
+		api.EXPECT().NetworkConnect(gomock.Any(), "testproject_default", "web1", &network.EndpointSettings{Aliases: []string{"web"}}).Return(nil),
+		api.EXPECT().NetworkConnect(gomock.Any(), "testproject_default", "foreign", &network.EndpointSettings{Aliases: []string{"foreign"}}).Return(nil),
+	)
+
+	err := tested.recreateNetwork(context.Background(), project, n, inspected)
+	assert.NilError(t, err)
+}
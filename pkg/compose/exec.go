@@ -140,6 +140,9 @@ func (s *composeService) interactiveExec(ctx context.Context, opts api.RunOption
 }
 
 func (s *composeService) getExecTarget(ctx context.Context, projectName string, opts api.RunOptions) (moby.Container, error) {
+	if opts.ContainerID != "" {
+		return moby.Container{ID: opts.ContainerID}, nil
+	}
 	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filters.NewArgs(
 			projectFilter(projectName),
@@ -0,0 +1,258 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/mattn/go-shellwords"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+const (
+	// extPostStart declares hooks to run inside service containers once started
+	extPostStart = "x-post_start"
+	// extPreStop declares hooks to run inside service containers before they get stopped
+	extPreStop = "x-pre_stop"
+
+	hookPostStart = "post_start"
+	hookPreStop   = "pre_stop"
+
+	// hookOnFailureFail makes a failed hook fail the whole operation, this is the default
+	hookOnFailureFail = "fail"
+	// hookOnFailureWarn only reports a failed hook as a warning
+	hookOnFailureWarn = "warn"
+)
+
+// serviceHook is a command run inside a service container at some point of its lifecycle
+type serviceHook struct {
+	Command     []string `json:"command"`
+	User        string   `json:"user,omitempty"`
+	Privileged  bool     `json:"privileged,omitempty"`
+	WorkingDir  string   `json:"working_dir,omitempty"`
+	Environment []string `json:"environment,omitempty"`
+	OnFailure   string   `json:"on_failure,omitempty"`
+}
+
+// getServiceHooks parses the hooks declared by service under the specified extension
+func getServiceHooks(service types.ServiceConfig, extension string) ([]serviceHook, error) {
+	declared, ok := service.Extensions[extension]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := declared.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("service %q: %s must be a list of hooks", service.Name, extension)
+	}
+	var hooks []serviceHook
+	for _, h := range list {
+		hook, err := parseServiceHook(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %q: invalid %s hook", service.Name, extension)
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+func parseServiceHook(declared interface{}) (serviceHook, error) {
+	var hook serviceHook
+	attributes, ok := declared.(map[string]interface{})
+	if !ok {
+		// short syntax only sets the command
+		attributes = map[string]interface{}{"command": declared}
+	}
+	for key, value := range attributes {
+		var err error
+		switch key {
+		case "command":
+			hook.Command, err = parseHookCommand(value)
+		case "user":
+			hook.User = fmt.Sprint(value)
+		case "privileged":
+			hook.Privileged, ok = value.(bool)
+			if !ok {
+				err = fmt.Errorf("privileged must be a boolean")
+			}
+		case "working_dir":
+			hook.WorkingDir = fmt.Sprint(value)
+		case "environment":
+			hook.Environment, err = parseHookEnvironment(value)
+		case "on_failure":
+			hook.OnFailure = fmt.Sprint(value)
+			if hook.OnFailure != hookOnFailureFail && hook.OnFailure != hookOnFailureWarn {
+				err = fmt.Errorf("on_failure must be one of %q or %q", hookOnFailureFail, hookOnFailureWarn)
+			}
+		default:
+			err = fmt.Errorf("unsupported attribute %q", key)
+		}
+		if err != nil {
+			return hook, err
+		}
+	}
+	if len(hook.Command) == 0 {
+		return hook, fmt.Errorf("command is required")
+	}
+	return hook, nil
+}
+
+func parseHookCommand(value interface{}) ([]string, error) {
+	switch command := value.(type) {
+	case string:
+		return shellwords.Parse(command)
+	case []interface{}:
+		var args []string
+		for _, arg := range command {
+			args = append(args, fmt.Sprint(arg))
+		}
+		return args, nil
+	}
+	return nil, fmt.Errorf("command must be a string or a list")
+}
+
+func parseHookEnvironment(value interface{}) ([]string, error) {
+	var env []string
+	switch environment := value.(type) {
+	case []interface{}:
+		for _, e := range environment {
+			env = append(env, fmt.Sprint(e))
+		}
+	case map[string]interface{}:
+		for k, v := range environment {
+			env = append(env, fmt.Sprintf("%s=%v", k, v))
+		}
+		sort.Strings(env)
+	default:
+		return nil, fmt.Errorf("environment must be a list or a mapping")
+	}
+	return env, nil
+}
+
+// getPreStopHooks returns the pre_stop hooks recorded on container by label, so they can run even when the
+// project has been rebuilt from labels
+func getPreStopHooks(container moby.Container) ([]serviceHook, error) {
+	label, ok := container.Labels[api.PreStopLabel]
+	if !ok || label == "" {
+		return nil, nil
+	}
+	var hooks []serviceHook
+	err := json.Unmarshal([]byte(label), &hooks)
+	return hooks, err
+}
+
+// runHooks runs hooks inside container using the exec API, output is forwarded to listener when set
+func (s *composeService) runHooks(ctx context.Context, container moby.Container, hooks []serviceHook, event string, listener api.ContainerEventListener) error {
+	if container.Labels[api.OneoffLabel] == "True" {
+		return nil
+	}
+	name := getCanonicalContainerName(container)
+	for _, hook := range hooks {
+		output := &hookOutput{
+			listener:  listener,
+			container: fmt.Sprintf("%s %s", name, event),
+			service:   container.Labels[api.ServiceLabel],
+		}
+		exitCode, err := s.Exec(ctx, container.Labels[api.ProjectLabel], api.RunOptions{
+			Service:     container.Labels[api.ServiceLabel],
+			ContainerID: container.ID,
+			Command:     hook.Command,
+			Environment: hook.Environment,
+			User:        hook.User,
+			Privileged:  hook.Privileged,
+			WorkingDir:  hook.WorkingDir,
+			Stdin:       ioutil.NopCloser(strings.NewReader("")),
+			Stdout:      output,
+			Stderr:      output,
+		})
+		output.Close() //nolint:errcheck
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("exit code %d", exitCode)
+		}
+		if err == nil {
+			continue
+		}
+		err = errors.Wrapf(err, "%s hook %q failed on container %s", event, strings.Join(hook.Command, " "), name)
+		if listener == nil && len(output.lines) > 0 {
+			err = errors.Errorf("%s:\n%s", err, strings.Join(output.lines, "\n"))
+		}
+		if hook.OnFailure == hookOnFailureWarn {
+			logrus.Warn(err)
+			continue
+		}
+		return err
+	}
+	return nil
+}
+
+// hookOutput forwards hook output line by line to a container event listener, or collects it when there's none
+type hookOutput struct {
+	mutex     sync.Mutex
+	listener  api.ContainerEventListener
+	container string
+	service   string
+	pending   string
+	lines     []string
+}
+
+func (o *hookOutput) Write(p []byte) (int, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.pending += string(p)
+	for {
+		i := strings.IndexByte(o.pending, '\n')
+		if i < 0 {
+			break
+		}
+		o.line(o.pending[:i])
+		o.pending = o.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+func (o *hookOutput) Close() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.pending != "" {
+		o.line(o.pending)
+		o.pending = ""
+	}
+	return nil
+}
+
+func (o *hookOutput) line(line string) {
+	if o.listener == nil {
+		o.lines = append(o.lines, line)
+		return
+	}
+	o.listener(api.ContainerEvent{
+		Type:      api.ContainerEventLog,
+		Container: o.container,
+		Service:   o.service,
+		Line:      line,
+	})
+}
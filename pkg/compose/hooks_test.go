@@ -0,0 +1,154 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestGetServiceHooks(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "db",
+		Extensions: map[string]interface{}{
+			extPostStart: []interface{}{
+				"psql -c 'CREATE USER app'",
+				map[string]interface{}{
+					"command":     []interface{}{"warm", "--all"},
+					"user":        "root",
+					"environment": map[string]interface{}{"B": 2, "A": "1"},
+					"on_failure":  "warn",
+				},
+			},
+		},
+	}
+	hooks, err := getServiceHooks(service, extPostStart)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hooks, []serviceHook{
+		{Command: []string{"psql", "-c", "CREATE USER app"}},
+		{Command: []string{"warm", "--all"}, User: "root", Environment: []string{"A=1", "B=2"}, OnFailure: hookOnFailureWarn},
+	})
+
+	hooks, err = getServiceHooks(service, extPreStop)
+	assert.NilError(t, err)
+	assert.Equal(t, len(hooks), 0)
+
+	service.Extensions[extPreStop] = []interface{}{map[string]interface{}{"command": "drain", "on_failure": "ignore"}}
+	_, err = getServiceHooks(service, extPreStop)
+	assert.ErrorContains(t, err, `on_failure must be one of "fail" or "warn"`)
+
+	service.Extensions[extPreStop] = []interface{}{map[string]interface{}{"user": "root"}}
+	_, err = getServiceHooks(service, extPreStop)
+	assert.ErrorContains(t, err, "command is required")
+}
+
+func TestPreStopLabel(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "web",
+		Extensions: map[string]interface{}{
+			extPreStop: []interface{}{"nginx -s quit"},
+		},
+	}
+	labels, err := tested.prepareLabels(&types.Project{Name: "test"}, service, 1)
+	assert.NilError(t, err)
+	hooks, err := getPreStopHooks(moby.Container{Labels: labels})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hooks, []serviceHook{{Command: []string{"nginx", "-s", "quit"}}})
+}
+
+func execResponse(t *testing.T, output string) moby.HijackedResponse {
+	var b bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&b, stdcopy.Stdout).Write([]byte(output))
+	assert.NilError(t, err)
+	conn, _ := net.Pipe()
+	return moby.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&b)}
+}
+
+func TestRunHooks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient}
+
+	container := testContainer("db", "123", false)
+	container.Names = []string{"/testproject-db-1"}
+	container.Labels[api.ContainerNumberLabel] = "1"
+
+	// hooks run in container, one-off containers of the service with the same number are not looked up
+	apiClient.EXPECT().ContainerExecCreate(gomock.Any(), "123", gomock.Any()).Return(moby.IDResponse{ID: "exec1"}, nil)
+	apiClient.EXPECT().ContainerExecAttach(gomock.Any(), "exec1", gomock.Any()).Return(execResponse(t, "user created\n"), nil)
+	apiClient.EXPECT().ContainerExecInspect(gomock.Any(), "exec1").Return(moby.ContainerExecInspect{ExitCode: 0}, nil)
+	apiClient.EXPECT().ContainerExecCreate(gomock.Any(), "123", gomock.Any()).Return(moby.IDResponse{ID: "exec2"}, nil)
+	apiClient.EXPECT().ContainerExecAttach(gomock.Any(), "exec2", gomock.Any()).Return(execResponse(t, "cache unavailable"), nil)
+	apiClient.EXPECT().ContainerExecInspect(gomock.Any(), "exec2").Return(moby.ContainerExecInspect{ExitCode: 1}, nil)
+
+	var events []api.ContainerEvent
+	hooks := []serviceHook{
+		{Command: []string{"create-user"}},
+		{Command: []string{"warm-cache"}, OnFailure: hookOnFailureWarn},
+	}
+	err := tested.runHooks(context.Background(), container, hooks, hookPostStart, func(event api.ContainerEvent) {
+		events = append(events, event)
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, events, []api.ContainerEvent{
+		{Type: api.ContainerEventLog, Container: "testproject-db-1 post_start", Service: "db", Line: "user created"},
+		{Type: api.ContainerEventLog, Container: "testproject-db-1 post_start", Service: "db", Line: "cache unavailable"},
+	})
+
+	apiClient.EXPECT().ContainerExecCreate(gomock.Any(), "123", gomock.Any()).Return(moby.IDResponse{ID: "exec3"}, nil)
+	apiClient.EXPECT().ContainerExecAttach(gomock.Any(), "exec3", gomock.Any()).Return(execResponse(t, "drain failed"), nil)
+	apiClient.EXPECT().ContainerExecInspect(gomock.Any(), "exec3").Return(moby.ContainerExecInspect{ExitCode: 2}, nil)
+
+	err = tested.runHooks(context.Background(), container, []serviceHook{{Command: []string{"drain"}}}, hookPreStop, nil)
+	assert.ErrorContains(t, err, `pre_stop hook "drain" failed on container testproject-db-1: exit code 2:
+drain failed`)
+}
+
+func TestRecreateRunsPreStopHooks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	tested := composeService{apiClient: apiClient}
+
+	replaced := testContainer("web", "123456789012", false)
+	replaced.Names = []string{"/testproject-web-1"}
+	replaced.State = ContainerRunning
+	replaced.Labels[api.ContainerNumberLabel] = "1"
+	replaced.Labels[api.PreStopLabel] = `[{"command":["drain"]}]`
+
+	// the container is left running when the hook fails, ContainerStop is never called
+	apiClient.EXPECT().ContainerExecCreate(gomock.Any(), "123456789012", gomock.Any()).Return(moby.IDResponse{ID: "exec1"}, nil)
+	apiClient.EXPECT().ContainerExecAttach(gomock.Any(), "exec1", gomock.Any()).Return(execResponse(t, "drain failed"), nil)
+	apiClient.EXPECT().ContainerExecInspect(gomock.Any(), "exec1").Return(moby.ContainerExecInspect{ExitCode: 1}, nil)
+
+	_, err := tested.recreateContainer(context.Background(), &types.Project{Name: "testproject"},
+		types.ServiceConfig{Name: "web"}, replaced, false, nil)
+	assert.ErrorContains(t, err, `pre_stop hook "drain" failed on container testproject-web-1`)
+}
@@ -0,0 +1,191 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/mattn/go-shellwords"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+const (
+	// extComposeHooks declares executables intercepting the project before compose commands run
+	extComposeHooks = "x-compose-hooks"
+	// interceptorsConfigKey lists, in the `compose` section of the docker config `plugins`, comma separated
+	// executables intercepting every project
+	interceptorsConfigKey = "interceptors"
+	// allowProjectHooksConfigKey opts in, in the `compose` section of the docker config `plugins`, to run the
+	// executables declared by projects, which are otherwise ignored as projects can't be trusted to run code
+	allowProjectHooksConfigKey = "allow-project-hooks"
+	// InterceptedCommandEnvVar tells interceptors the compose command they are run for
+	InterceptedCommandEnvVar = "COMPOSE_COMMAND"
+)
+
+// NewExecutableInterceptor returns an api.Interceptor running the executables declared in the docker config file, then
+// the ones declared by the project x-compose-hooks extension when the docker config allows it. Each gets the project
+// as JSON on stdin and can print a modified project on stdout, or exit with a non-zero status to reject it, stderr
+// being the reason
+func NewExecutableInterceptor(configFile *configfile.ConfigFile) api.Interceptor {
+	return func(ctx context.Context, command string, project *types.Project) error {
+		interceptors, err := getInterceptors(configFile, project)
+		if err != nil {
+			return err
+		}
+		for _, interceptor := range interceptors {
+			if err := runInterceptor(ctx, interceptor, command, project); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func getInterceptors(configFile *configfile.ConfigFile, project *types.Project) ([][]string, error) {
+	var interceptors [][]string
+	if configFile != nil {
+		for _, declared := range strings.Split(configFile.Plugins["compose"][interceptorsConfigKey], ",") {
+			if strings.TrimSpace(declared) == "" {
+				continue
+			}
+			args, err := shellwords.Parse(declared)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid interceptor %q in docker config", declared)
+			}
+			interceptors = append(interceptors, args)
+		}
+	}
+	declared, ok := project.Extensions[extComposeHooks]
+	if !ok {
+		return interceptors, nil
+	}
+	if configFile == nil || configFile.Plugins["compose"][allowProjectHooksConfigKey] != "true" {
+		logrus.Warnf("%s ignored, set %q to \"true\" in the compose plugin section of the docker config to run "+
+			"executables declared by projects", extComposeHooks, allowProjectHooksConfigKey)
+		return interceptors, nil
+	}
+	list, ok := declared.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of commands", extComposeHooks)
+	}
+	for _, d := range list {
+		args, err := parseHookCommand(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s entry", extComposeHooks)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("invalid %s entry: command is required", extComposeHooks)
+		}
+		interceptors = append(interceptors, args)
+	}
+	return interceptors, nil
+}
+
+// runInterceptor pipes project to the interceptor executable, relative paths being resolved from the project
+// working directory, and replaces project with the one it printed, if any
+func runInterceptor(ctx context.Context, interceptor []string, command string, project *types.Project) error {
+	input, err := projectToJSON(project)
+	if err != nil {
+		return err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, interceptor[0], interceptor[1:]...)
+	cmd.Dir = project.WorkingDir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", InterceptedCommandEnvVar, command))
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return errors.Wrapf(err, "cannot run interceptor %s", interceptor[0])
+		}
+		reason := strings.TrimSpace(stderr.String())
+		if reason == "" {
+			reason = err.Error()
+		}
+		return fmt.Errorf("project rejected by %s: %s", interceptor[0], reason)
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	modified, err := projectFromJSON(project, stdout.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "invalid project returned by %s", interceptor[0])
+	}
+	*project = *modified
+	return nil
+}
+
+// projectToJSON goes through the yaml model, as the JSON one of compose-go ignores extensions
+func projectToJSON(project *types.Project) ([]byte, error) {
+	marshal, err := yaml.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+	dict, err := loader.ParseYAML(marshal)
+	if err != nil {
+		return nil, err
+	}
+	dict["name"] = project.Name
+	return json.Marshal(dict)
+}
+
+// projectFromJSON loads the project printed by an interceptor, as JSON or yaml, and restores the attributes of the
+// original one which are not part of the compose model
+func projectFromJSON(original *types.Project, content []byte) (*types.Project, error) {
+	dict, err := loader.ParseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+	// only the original project name is used
+	delete(dict, "name")
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  original.WorkingDir,
+		ConfigFiles: []types.ConfigFile{{Filename: "-", Config: dict}},
+		Environment: original.Environment,
+	}, func(options *loader.Options) {
+		options.Name = original.Name
+		options.SkipInterpolation = true
+		options.SkipNormalization = true
+	})
+	if err != nil {
+		return nil, err
+	}
+	project.ComposeFiles = original.ComposeFiles
+	project.Environment = original.Environment
+	project.DisabledServices = original.DisabledServices
+	for i, service := range project.Services {
+		if o, err := original.GetService(service.Name); err == nil {
+			project.Services[i].CustomLabels = o.CustomLabels
+			project.Services[i].Scale = o.Scale
+		}
+	}
+	return project, nil
+}
@@ -0,0 +1,136 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/v3/assert"
+)
+
+func writeInterceptor(t *testing.T, dir, name, script string) string {
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+	return path
+}
+
+func interceptedProject(dir string) *types.Project {
+	return &types.Project{
+		Name:       "myproject",
+		WorkingDir: dir,
+		Services: types.Services{
+			{
+				Name:         "web",
+				Image:        "nginx",
+				CustomLabels: types.Labels{"com.docker.compose.project": "myproject"},
+				Extensions:   map[string]interface{}{"x-team": "frontend"},
+			},
+		},
+		Extensions: map[string]interface{}{},
+	}
+}
+
+// allowProjectHooks is a docker config running the interceptors declared by projects
+func allowProjectHooks() *configfile.ConfigFile {
+	return &configfile.ConfigFile{
+		Plugins: map[string]map[string]string{"compose": {allowProjectHooksConfigKey: "true"}},
+	}
+}
+
+func TestInterceptorRejectsProject(t *testing.T) {
+	dir := t.TempDir()
+	writeInterceptor(t, dir, "policy", `
+test "$COMPOSE_COMMAND" = up || exit 0
+grep -q '"privileged":true' && { echo "privileged mode is banned" >&2; exit 1; }
+exit 0
+`)
+	project := interceptedProject(dir)
+	project.Services[0].Privileged = true
+	project.Extensions[extComposeHooks] = []interface{}{"./policy"}
+
+	intercept := NewExecutableInterceptor(allowProjectHooks())
+	err := intercept(context.Background(), "up", project)
+	assert.Error(t, err, "project rejected by ./policy: privileged mode is banned")
+
+	assert.NilError(t, intercept(context.Background(), "build", project))
+}
+
+func TestInterceptorIgnoresProjectHooks(t *testing.T) {
+	dir := t.TempDir()
+	writeInterceptor(t, dir, "policy", `
+echo "project hooks must not run" >&2
+exit 1
+`)
+	project := interceptedProject(dir)
+	project.Extensions[extComposeHooks] = []interface{}{"./policy"}
+
+	assert.NilError(t, NewExecutableInterceptor(nil)(context.Background(), "up", project))
+	configFile := &configfile.ConfigFile{
+		Plugins: map[string]map[string]string{"compose": {allowProjectHooksConfigKey: "false"}},
+	}
+	assert.NilError(t, NewExecutableInterceptor(configFile)(context.Background(), "up", project))
+}
+
+func TestInterceptorModifiesProject(t *testing.T) {
+	dir := t.TempDir()
+	path := writeInterceptor(t, dir, "labels", `
+cat > /dev/null
+echo '{"services": {"web": {"image": "nginx:1.21", "labels": {"team": "frontend"}, "x-team": "frontend"}}}'
+`)
+	project := interceptedProject(dir)
+	configFile := &configfile.ConfigFile{
+		Plugins: map[string]map[string]string{"compose": {"interceptors": path}},
+	}
+
+	err := NewExecutableInterceptor(configFile)(context.Background(), "create", project)
+	assert.NilError(t, err)
+	assert.Equal(t, project.Name, "myproject")
+	assert.Equal(t, project.WorkingDir, dir)
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, web.Image, "nginx:1.21")
+	assert.DeepEqual(t, web.Labels, types.Labels{"team": "frontend"})
+	assert.DeepEqual(t, web.CustomLabels, types.Labels{"com.docker.compose.project": "myproject"})
+	assert.DeepEqual(t, web.Extensions, map[string]interface{}{"x-team": "frontend"})
+}
+
+func TestInterceptorRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	project := interceptedProject(dir)
+	project.Extensions[extComposeHooks] = []interface{}{[]interface{}{"cat"}}
+
+	err := NewExecutableInterceptor(allowProjectHooks())(context.Background(), "up", project)
+	assert.NilError(t, err)
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, web.Image, "nginx")
+	assert.DeepEqual(t, web.Extensions, map[string]interface{}{"x-team": "frontend"})
+	assert.DeepEqual(t, project.Extensions[extComposeHooks], []interface{}{[]interface{}{"cat"}})
+}
+
+func TestInterceptorInvalidDeclaration(t *testing.T) {
+	project := interceptedProject(t.TempDir())
+	project.Extensions[extComposeHooks] = "./policy"
+
+	err := NewExecutableInterceptor(allowProjectHooks())(context.Background(), "up", project)
+	assert.Error(t, err, "x-compose-hooks must be a list of commands")
+}
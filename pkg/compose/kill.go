@@ -47,21 +47,24 @@ func (s *composeService) kill(ctx context.Context, project *types.Project, optio
 		return err
 	}
 
-	eg, ctx := errgroup.WithContext(ctx)
-	containers.
-		filter(isService(project.ServiceNames()...)).
-		forEach(func(container moby.Container) {
-			eg.Go(func() error {
-				eventName := getContainerProgressName(container)
-				w.Event(progress.KillingEvent(eventName))
-				err := s.apiClient.ContainerKill(ctx, container.ID, options.Signal)
-				if err != nil {
-					w.Event(progress.ErrorMessageEvent(eventName, "Error while Killing"))
-					return err
-				}
-				w.Event(progress.KilledEvent(eventName))
-				return nil
+	// dependents are killed first, so they don't observe their dependencies failing
+	return InReverseDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		eg, ctx := errgroup.WithContext(ctx)
+		containers.
+			filter(isService(service)).
+			forEach(func(container moby.Container) {
+				eg.Go(func() error {
+					eventName := getContainerProgressName(container)
+					w.Event(progress.KillingEvent(eventName))
+					err := s.apiClient.ContainerKill(ctx, container.ID, options.Signal)
+					if err != nil {
+						w.Event(progress.ErrorMessageEvent(eventName, "Error while Killing"))
+						return err
+					}
+					w.Event(progress.KilledEvent(eventName))
+					return nil
+				})
 			})
-		})
-	return eg.Wait()
+		return eg.Wait()
+	})
 }
@@ -0,0 +1,422 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/distribution/distribution/v3/reference"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// extLint configures lint rules levels for the project, as a `rules` mapping of rule IDs to levels
+const extLint = "x-lint"
+
+// lintProblem is reported by a rule check, path locating it in the compose files
+type lintProblem struct {
+	path    []string
+	message string
+}
+
+type lintRule struct {
+	api.LintRule
+	check func(project *types.Project) []lintProblem
+}
+
+var lintRules = []lintRule{
+	{
+		LintRule: api.LintRule{ID: "privileged", Level: api.LintLevelWarning,
+			Description: "Services should not run privileged containers"},
+		check: lintPrivileged,
+	},
+	{
+		LintRule: api.LintRule{ID: "host-network", Level: api.LintLevelWarning,
+			Description: "Services should not use the host network"},
+		check: lintHostNetwork,
+	},
+	{
+		LintRule: api.LintRule{ID: "latest-tag", Level: api.LintLevelWarning,
+			Description: "Images should be pinned to a tag other than latest, or to a digest"},
+		check: lintLatestTag,
+	},
+	{
+		LintRule: api.LintRule{ID: "missing-healthcheck", Level: api.LintLevelWarning,
+			Description: "Services others depend on with condition service_healthy should declare a healthcheck"},
+		check: lintMissingHealthcheck,
+	},
+	{
+		LintRule: api.LintRule{ID: "public-port", Level: api.LintLevelNote,
+			Description: "Ports should be published on a specific host IP rather than all interfaces"},
+		check: lintPublicPort,
+	},
+	{
+		LintRule: api.LintRule{ID: "unused-volume", Level: api.LintLevelNote,
+			Description: "Declared volumes should be used by a service"},
+		check: lintUnusedVolume,
+	},
+	{
+		LintRule: api.LintRule{ID: "secret-env", Level: api.LintLevelWarning,
+			Description: "Secrets should not be passed as environment variables"},
+		check: lintSecretEnv,
+	},
+	{
+		LintRule: api.LintRule{ID: "dependency-cycle", Level: api.LintLevelError,
+			Description: "Service dependencies must not form a cycle"},
+		check: lintDependencyCycle,
+	},
+}
+
+// LintRules returns the rules run by Lint, with their default level
+func LintRules() []api.LintRule {
+	var rules []api.LintRule
+	for _, rule := range lintRules {
+		rules = append(rules, rule.LintRule)
+	}
+	return rules
+}
+
+// Lint runs the enabled rules over project, and returns findings ordered by position in the compose files, in the
+// order they're loaded
+func (s *composeService) Lint(_ context.Context, project *types.Project, options api.LintOptions) ([]api.LintFinding, error) {
+	levels, err := getLintLevels(project, options)
+	if err != nil {
+		return nil, err
+	}
+	locator := newLintLocator(project.ComposeFiles)
+	var findings []api.LintFinding
+	for _, rule := range lintRules {
+		level := levels[rule.ID]
+		if level == api.LintLevelOff {
+			continue
+		}
+		for _, problem := range rule.check(project) {
+			file, line, column := locator.locate(problem.path)
+			findings = append(findings, api.LintFinding{
+				Rule:    rule.ID,
+				Level:   level,
+				Message: problem.message,
+				File:    file,
+				Line:    line,
+				Column:  column,
+			})
+		}
+	}
+	order := map[string]int{}
+	for i, file := range project.ComposeFiles {
+		order[file] = i + 1
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			// findings which couldn't be located come last
+			return a.File != "" && (b.File == "" || order[a.File] < order[b.File])
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return findings, nil
+}
+
+// getLintLevels resolves rules level from their default, then the x-lint extension, then options
+func getLintLevels(project *types.Project, options api.LintOptions) (map[string]string, error) {
+	levels := map[string]string{}
+	for _, rule := range lintRules {
+		levels[rule.ID] = rule.Level
+	}
+	set := func(id string, level string) error {
+		if _, ok := levels[id]; !ok {
+			return fmt.Errorf("unknown lint rule %q", id)
+		}
+		switch level {
+		case api.LintLevelError, api.LintLevelWarning, api.LintLevelNote, api.LintLevelOff:
+			levels[id] = level
+			return nil
+		}
+		return fmt.Errorf("invalid level %q for lint rule %q", level, id)
+	}
+	if declared, ok := project.Extensions[extLint]; ok {
+		config, ok := declared.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be a mapping", extLint)
+		}
+		rules, ok := config["rules"].(map[string]interface{})
+		if !ok && config["rules"] != nil {
+			return nil, fmt.Errorf("%s rules must be a mapping of rule IDs to levels", extLint)
+		}
+		for id, value := range rules {
+			level := fmt.Sprint(value)
+			if value == false {
+				// yaml parses a bare `off` as a boolean
+				level = api.LintLevelOff
+			}
+			if err := set(id, level); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for id, level := range options.Levels {
+		if err := set(id, level); err != nil {
+			return nil, err
+		}
+	}
+	return levels, nil
+}
+
+func lintPrivileged(project *types.Project) []lintProblem {
+	var problems []lintProblem
+	for _, service := range project.Services {
+		if service.Privileged {
+			problems = append(problems, lintProblem{
+				path:    []string{"services", service.Name, "privileged"},
+				message: fmt.Sprintf("service %q runs privileged containers", service.Name),
+			})
+		}
+	}
+	return problems
+}
+
+func lintHostNetwork(project *types.Project) []lintProblem {
+	var problems []lintProblem
+	for _, service := range project.Services {
+		if service.NetworkMode == "host" {
+			problems = append(problems, lintProblem{
+				path:    []string{"services", service.Name, "network_mode"},
+				message: fmt.Sprintf("service %q uses the host network", service.Name),
+			})
+		}
+	}
+	return problems
+}
+
+func lintLatestTag(project *types.Project) []lintProblem {
+	var problems []lintProblem
+	for _, service := range project.Services {
+		if service.Image == "" {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(service.Image)
+		if err != nil {
+			continue
+		}
+		if _, ok := named.(reference.Canonical); ok {
+			continue
+		}
+		if tagged, ok := named.(reference.Tagged); ok && tagged.Tag() != "latest" {
+			continue
+		}
+		problems = append(problems, lintProblem{
+			path:    []string{"services", service.Name, "image"},
+			message: fmt.Sprintf("service %q uses image %s with the latest tag", service.Name, service.Image),
+		})
+	}
+	return problems
+}
+
+func lintMissingHealthcheck(project *types.Project) []lintProblem {
+	var problems []lintProblem
+	for _, service := range project.Services {
+		var dependents []string
+		for _, s := range project.Services {
+			if dependency, ok := s.DependsOn[service.Name]; ok && dependency.Condition == types.ServiceConditionHealthy {
+				dependents = append(dependents, s.Name)
+			}
+		}
+		if len(dependents) == 0 || service.HealthCheck != nil && !service.HealthCheck.Disable {
+			continue
+		}
+		problems = append(problems, lintProblem{
+			path: []string{"services", service.Name},
+			message: fmt.Sprintf("service %q declares no healthcheck, required by the %s condition of %s",
+				service.Name, types.ServiceConditionHealthy, strings.Join(dependents, ", ")),
+		})
+	}
+	return problems
+}
+
+func lintPublicPort(project *types.Project) []lintProblem {
+	var problems []lintProblem
+	for _, service := range project.Services {
+		for _, port := range service.Ports {
+			if port.HostIP != "" && port.HostIP != "0.0.0.0" && port.HostIP != "::" {
+				continue
+			}
+			problems = append(problems, lintProblem{
+				path: []string{"services", service.Name, "ports"},
+				message: fmt.Sprintf("service %q publishes port %d on all interfaces, set a host IP like 127.0.0.1",
+					service.Name, port.Target),
+			})
+		}
+	}
+	return problems
+}
+
+func lintUnusedVolume(project *types.Project) []lintProblem {
+	used := map[string]bool{}
+	for _, service := range project.AllServices() {
+		for _, volume := range service.Volumes {
+			if volume.Type == types.VolumeTypeVolume {
+				used[volume.Source] = true
+			}
+		}
+	}
+	var problems []lintProblem
+	for _, name := range sortedVolumeNames(project.Volumes) {
+		if !used[name] {
+			problems = append(problems, lintProblem{
+				path:    []string{"volumes", name},
+				message: fmt.Sprintf("volume %q is not used by any service", name),
+			})
+		}
+	}
+	return problems
+}
+
+var secretEnvPattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|CREDENTIALS?)`)
+
+func lintSecretEnv(project *types.Project) []lintProblem {
+	var problems []lintProblem
+	for _, service := range project.Services {
+		var names []string
+		for name, value := range service.Environment {
+			// *_FILE variables conventionally point to a mounted secret
+			if value == nil || *value == "" || strings.HasSuffix(strings.ToUpper(name), "_FILE") {
+				continue
+			}
+			if secretEnvPattern.MatchString(name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			problems = append(problems, lintProblem{
+				path:    []string{"services", service.Name, "environment", name},
+				message: fmt.Sprintf("service %q passes %s as an environment variable, use a secret instead", service.Name, name),
+			})
+		}
+	}
+	return problems
+}
+
+func lintDependencyCycle(project *types.Project) []lintProblem {
+	graph := NewGraph(project.Services, ServiceStopped)
+	if cycle, err := graph.HasCycles(); cycle {
+		service := strings.Split(strings.TrimPrefix(err.Error(), "cycle found: "), " -> ")[0]
+		return []lintProblem{{
+			path:    []string{"services", service, "depends_on"},
+			message: err.Error(),
+		}}
+	}
+	return nil
+}
+
+func sortedVolumeNames(volumes types.Volumes) []string {
+	var keys []string
+	for name := range volumes {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lintLocator finds the position of attributes in the compose files, as the compose model doesn't keep them
+type lintLocator struct {
+	files []string
+	nodes []*yaml.Node
+}
+
+func newLintLocator(files []string) *lintLocator {
+	locator := &lintLocator{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(content, &node); err != nil {
+			continue
+		}
+		locator.files = append(locator.files, file)
+		locator.nodes = append(locator.nodes, &node)
+	}
+	return locator
+}
+
+// locate returns the position of the deepest attribute of path found in the compose files, the last file declaring
+// it winning as it overrides the previous ones
+func (l *lintLocator) locate(path []string) (string, int, int) {
+	var (
+		file         string
+		line, column int
+		depth        int
+	)
+	for i, node := range l.nodes {
+		d, found := lookupNode(node, path)
+		if found != nil && d >= depth {
+			file, line, column, depth = l.files[i], found.Line, found.Column, d
+		}
+	}
+	return file, line, column
+}
+
+func lookupNode(node *yaml.Node, path []string) (int, *yaml.Node) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	var found *yaml.Node
+	depth := 0
+	for _, key := range path {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		next, keyNode := childNode(node, key)
+		if next == nil {
+			break
+		}
+		found, node = keyNode, next
+		depth++
+	}
+	return depth, found
+}
+
+// childNode returns the value of key in a mapping, or the `key=value` item of a list, with the node to report
+func childNode(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1], node.Content[i]
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode && (item.Value == key || strings.HasPrefix(item.Value, key+"=")) {
+				return item, item
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,180 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func lintedProject() *types.Project {
+	password := "example"
+	return &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			{
+				Name:  "db",
+				Image: "postgres:14",
+				Environment: types.MappingWithEquals{
+					"POSTGRES_PASSWORD":      &password,
+					"POSTGRES_PASSWORD_FILE": &password,
+					"POSTGRES_USER":          &password,
+				},
+				NetworkMode: "host",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/var/lib/postgresql/data"},
+				},
+			},
+			{
+				Name:       "web",
+				Image:      "nginx",
+				Privileged: true,
+				Ports: []types.ServicePortConfig{
+					{Target: 80, Published: 8080},
+					{Target: 443, Published: 8443, HostIP: "127.0.0.1"},
+				},
+				DependsOn: types.DependsOnConfig{
+					"db": {Condition: types.ServiceConditionHealthy},
+				},
+			},
+			{
+				Name:  "worker",
+				Image: "worker@sha256:9d1c4b1a0e5f2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d",
+			},
+		},
+		Volumes: types.Volumes{
+			"data":  {},
+			"cache": {},
+		},
+	}
+}
+
+func lintMessages(findings []api.LintFinding) map[string][]string {
+	messages := map[string][]string{}
+	for _, finding := range findings {
+		messages[finding.Rule] = append(messages[finding.Rule], finding.Level+": "+finding.Message)
+	}
+	return messages
+}
+
+func TestLintRules(t *testing.T) {
+	findings, err := tested.Lint(context.Background(), lintedProject(), api.LintOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, lintMessages(findings), map[string][]string{
+		"privileged":   {`warning: service "web" runs privileged containers`},
+		"host-network": {`warning: service "db" uses the host network`},
+		"latest-tag":   {`warning: service "web" uses image nginx with the latest tag`},
+		"missing-healthcheck": {
+			`warning: service "db" declares no healthcheck, required by the service_healthy condition of web`,
+		},
+		"public-port": {
+			`note: service "web" publishes port 80 on all interfaces, set a host IP like 127.0.0.1`,
+		},
+		"unused-volume": {`note: volume "cache" is not used by any service`},
+		"secret-env": {
+			`warning: service "db" passes POSTGRES_PASSWORD as an environment variable, use a secret instead`,
+		},
+	})
+}
+
+func TestLintDependencyCycle(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "a", Image: "a:1", DependsOn: types.DependsOnConfig{"b": {Condition: types.ServiceConditionStarted}}},
+			{Name: "b", Image: "b:1", DependsOn: types.DependsOnConfig{"a": {Condition: types.ServiceConditionStarted}}},
+		},
+	}
+	findings, err := tested.Lint(context.Background(), project, api.LintOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(findings), 1)
+	assert.Equal(t, findings[0].Rule, "dependency-cycle")
+	assert.Equal(t, findings[0].Level, api.LintLevelError)
+	assert.Check(t, cmp.Contains(findings[0].Message, "cycle found: "))
+}
+
+func TestLintLevels(t *testing.T) {
+	project := lintedProject()
+	project.Extensions = map[string]interface{}{
+		extLint: map[string]interface{}{
+			"rules": map[string]interface{}{
+				"latest-tag":  "error",
+				"public-port": false,
+				"privileged":  "note",
+			},
+		},
+	}
+	findings, err := tested.Lint(context.Background(), project, api.LintOptions{Levels: map[string]string{
+		"privileged":    api.LintLevelError,
+		"unused-volume": api.LintLevelOff,
+	}})
+	assert.NilError(t, err)
+	messages := lintMessages(findings)
+	assert.DeepEqual(t, messages["latest-tag"], []string{`error: service "web" uses image nginx with the latest tag`})
+	assert.DeepEqual(t, messages["privileged"], []string{`error: service "web" runs privileged containers`})
+	assert.Check(t, messages["public-port"] == nil)
+	assert.Check(t, messages["unused-volume"] == nil)
+
+	_, err = tested.Lint(context.Background(), project, api.LintOptions{Levels: map[string]string{"bogus": api.LintLevelOff}})
+	assert.Error(t, err, `unknown lint rule "bogus"`)
+	_, err = tested.Lint(context.Background(), project, api.LintOptions{Levels: map[string]string{"privileged": "fatal"}})
+	assert.Error(t, err, `invalid level "fatal" for lint rule "privileged"`)
+}
+
+func TestLintLocations(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, ioutil.WriteFile(base, []byte(`services:
+  web:
+    image: nginx
+  db:
+    image: postgres:14
+    environment:
+      - POSTGRES_PASSWORD=example
+`), 0o644))
+	override := filepath.Join(dir, "compose.override.yaml")
+	assert.NilError(t, ioutil.WriteFile(override, []byte(`services:
+  web:
+    privileged: true
+`), 0o644))
+
+	password := "example"
+	project := &types.Project{
+		ComposeFiles: []string{base, override},
+		Services: types.Services{
+			{Name: "db", Image: "postgres:14", Environment: types.MappingWithEquals{"POSTGRES_PASSWORD": &password}},
+			{Name: "web", Image: "nginx", Privileged: true},
+		},
+	}
+	findings, err := tested.Lint(context.Background(), project, api.LintOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, findings, []api.LintFinding{
+		{Rule: "latest-tag", Level: api.LintLevelWarning, File: base, Line: 3, Column: 5,
+			Message: `service "web" uses image nginx with the latest tag`},
+		{Rule: "secret-env", Level: api.LintLevelWarning, File: base, Line: 7, Column: 9,
+			Message: `service "db" passes POSTGRES_PASSWORD as an environment variable, use a secret instead`},
+		{Rule: "privileged", Level: api.LintLevelWarning, File: override, Line: 3, Column: 5,
+			Message: `service "web" runs privileged containers`},
+	})
+}
@@ -18,31 +18,54 @@ package compose
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/utils"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 )
 
 func (s *composeService) Logs(ctx context.Context, projectName string, consumer api.LogConsumer, options api.LogOptions) error {
+	filter, err := newLogFilter(options)
+	if err != nil {
+		return err
+	}
+
 	containers, err := s.getContainers(ctx, projectName, oneOffExclude, true, options.Services...)
 	if err != nil {
 		return err
 	}
 
+	var orderer *logsOrderer
+	if options.Ordered {
+		orderer = newLogsOrderer(consumer, options.Timestamps)
+		consumer = orderer
+		if options.Follow {
+			done := make(chan struct{})
+			defer close(done)
+			go orderer.watch(done)
+		}
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 	for _, c := range containers {
 		c := c
 		eg.Go(func() error {
-			return s.logContainers(ctx, consumer, c, options)
+			return s.logContainers(ctx, consumer, c, options, filter)
 		})
 	}
 
 	if options.Follow {
-		printer := newLogPrinter(consumer)
+		printer := newLogPrinter(consumer, os.Stdout)
 		eg.Go(func() error {
 			for _, c := range containers {
 				printer.HandleEvent(api.ContainerEvent{
@@ -55,31 +78,38 @@ func (s *composeService) Logs(ctx context.Context, projectName string, consumer
 		})
 
 		eg.Go(func() error {
-			return s.watchContainers(ctx, projectName, options.Services, printer.HandleEvent, containers, func(c types.Container) error {
+			return s.watchContainers(ctx, projectName, options.Services, printer.HandleEvent, containers, nil, func(c types.Container) error {
 				printer.HandleEvent(api.ContainerEvent{
 					Type:      api.ContainerEventAttach,
 					Container: getContainerNameWithoutProject(c),
 					Service:   c.Labels[api.ServiceLabel],
 				})
-				return s.logContainers(ctx, consumer, c, options)
+				return s.logContainers(ctx, consumer, c, options, filter)
 			})
 		})
 
 		eg.Go(func() error {
-			_, err := printer.Run(ctx, false, "", nil)
+			_, err := printer.Run(ctx, api.StartOptions{}, nil)
 			return err
 		})
 	}
 
-	return eg.Wait()
+	err = eg.Wait()
+	if orderer != nil {
+		orderer.flush(time.Time{})
+	}
+	return err
 }
 
-func (s *composeService) logContainers(ctx context.Context, consumer api.LogConsumer, c types.Container, options api.LogOptions) error {
+func (s *composeService) logContainers(ctx context.Context, consumer api.LogConsumer, c types.Container, options api.LogOptions, filter logFilter) error {
 	cnt, err := s.apiClient.ContainerInspect(ctx, c.ID)
 	if err != nil {
 		return err
 	}
 
+	entryConsumer, structured := consumer.(api.LogEntryConsumer)
+	timestamps := options.Timestamps || structured
+
 	service := c.Labels[api.ServiceLabel]
 	r, err := s.apiClient.ContainerLogs(ctx, cnt.ID, types.ContainerLogsOptions{
 		ShowStdout: true,
@@ -88,7 +118,7 @@ func (s *composeService) logContainers(ctx context.Context, consumer api.LogCons
 		Since:      options.Since,
 		Until:      options.Until,
 		Tail:       options.Tail,
-		Timestamps: options.Timestamps,
+		Timestamps: timestamps,
 	})
 	if err != nil {
 		return err
@@ -96,13 +126,116 @@ func (s *composeService) logContainers(ctx context.Context, consumer api.LogCons
 	defer r.Close() // nolint errcheck
 
 	name := getContainerNameWithoutProject(c)
-	w := utils.GetWriter(func(line string) {
-		consumer.Log(name, service, line)
-	})
+	replica, _ := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+	writer := func(stream string) io.WriteCloser {
+		return utils.GetWriter(func(line string) {
+			entry := api.LogEntry{
+				Service:   service,
+				Container: name,
+				Replica:   replica,
+				Stream:    stream,
+				Message:   line,
+			}
+			if timestamps {
+				entry.Timestamp, entry.Message = splitLogTimestamp(line)
+			}
+			if !filter.match(entry.Message) {
+				return
+			}
+			if structured {
+				entryConsumer.LogEntry(entry)
+				return
+			}
+			consumer.Log(name, service, line)
+		})
+	}
+	stdout, stderr := writer("stdout"), writer("stderr")
+	defer stdout.Close() // nolint errcheck
+	defer stderr.Close() // nolint errcheck
 	if cnt.Config.Tty {
-		_, err = io.Copy(w, r)
+		_, err = io.Copy(stdout, r)
 	} else {
-		_, err = stdcopy.StdCopy(w, w, r)
+		_, err = stdcopy.StdCopy(stdout, stderr, r)
 	}
 	return err
 }
+
+// splitLogTimestamp separates the RFC3339 timestamp the engine prefixes log lines with from the actual message
+func splitLogTimestamp(line string) (time.Time, string) {
+	parts := strings.SplitN(line, " ", 2)
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, line
+	}
+	if len(parts) == 1 {
+		return t, ""
+	}
+	return t, parts[1]
+}
+
+// logFilter selects log lines according to LogOptions Grep and Level
+type logFilter struct {
+	grep  *regexp.Regexp
+	level int
+}
+
+var logLevels = map[string]int{
+	"trace":    1,
+	"debug":    2,
+	"info":     3,
+	"notice":   3,
+	"warn":     4,
+	"warning":  4,
+	"error":    5,
+	"err":      5,
+	"critical": 6,
+	"crit":     6,
+	"fatal":    6,
+	"panic":    6,
+}
+
+func newLogFilter(options api.LogOptions) (logFilter, error) {
+	var filter logFilter
+	if options.Grep != "" {
+		grep, err := regexp.Compile(options.Grep)
+		if err != nil {
+			return filter, errors.Wrapf(api.ErrParsingFailed, "invalid grep expression %q: %v", options.Grep, err)
+		}
+		filter.grep = grep
+	}
+	if options.Level != "" {
+		level, ok := logLevels[strings.ToLower(options.Level)]
+		if !ok {
+			return filter, errors.Wrapf(api.ErrParsingFailed, "unknown log level %q", options.Level)
+		}
+		filter.level = level
+	}
+	return filter, nil
+}
+
+func (f logFilter) match(message string) bool {
+	if f.grep != nil && !f.grep.MatchString(message) {
+		return false
+	}
+	if f.level > 0 {
+		if level, ok := jsonLogLevel(message); ok && level < f.level {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonLogLevel extracts severity from a JSON formatted log line, as set by common logging libraries
+func jsonLogLevel(message string) (int, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(message), &fields); err != nil {
+		return 0, false
+	}
+	for _, key := range []string{"level", "lvl", "severity"} {
+		if value, ok := fields[key].(string); ok {
+			level, ok := logLevels[strings.ToLower(value)]
+			return level, ok
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,157 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+const (
+	// logsReorderDelay is the time a log entry is retained to let older entries from other containers arrive
+	logsReorderDelay = time.Second
+	// logsReorderBufferSize bounds the number of retained log entries
+	logsReorderBufferSize = 10000
+)
+
+// logsOrderer collects log entries from all containers and forwards them to consumer by timestamp order
+type logsOrderer struct {
+	consumer   api.LogConsumer
+	timestamps bool
+	mutex      sync.Mutex
+	entries    logEntryHeap
+	sequence   int
+}
+
+func newLogsOrderer(consumer api.LogConsumer, timestamps bool) *logsOrderer {
+	return &logsOrderer{
+		consumer:   consumer,
+		timestamps: timestamps,
+	}
+}
+
+var _ api.LogEntryConsumer = &logsOrderer{}
+
+// LogEntry implements api.LogEntryConsumer
+func (o *logsOrderer) LogEntry(entry api.LogEntry) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.sequence++
+	heap.Push(&o.entries, orderedLogEntry{
+		LogEntry: entry,
+		received: time.Now(),
+		sequence: o.sequence,
+	})
+	for o.entries.Len() > logsReorderBufferSize {
+		o.forward(heap.Pop(&o.entries).(orderedLogEntry).LogEntry)
+	}
+}
+
+// Log implements api.LogConsumer, lines without a timestamp can't be ordered and are forwarded as is
+func (o *logsOrderer) Log(container, service, message string) {
+	o.consumer.Log(container, service, message)
+}
+
+// Status implements api.LogConsumer, flushing pending entries so status is displayed after the container last logs
+func (o *logsOrderer) Status(container, msg string) {
+	o.flush(time.Time{})
+	o.consumer.Status(container, msg)
+}
+
+// Register implements api.LogConsumer
+func (o *logsOrderer) Register(container string) {
+	o.consumer.Register(container)
+}
+
+// flush forwards entries received before deadline, or all entries when deadline is zero
+func (o *logsOrderer) flush(deadline time.Time) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	for o.entries.Len() > 0 {
+		if !deadline.IsZero() && o.entries[0].received.After(deadline) {
+			return
+		}
+		o.forward(heap.Pop(&o.entries).(orderedLogEntry).LogEntry)
+	}
+}
+
+// watch periodically forwards entries retained for longer than logsReorderDelay, until done is closed
+func (o *logsOrderer) watch(done <-chan struct{}) {
+	ticker := time.NewTicker(logsReorderDelay / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			o.flush(now.Add(-logsReorderDelay))
+		}
+	}
+}
+
+func (o *logsOrderer) forward(entry api.LogEntry) {
+	if c, ok := o.consumer.(api.LogEntryConsumer); ok {
+		c.LogEntry(entry)
+		return
+	}
+	line := entry.Message
+	if o.timestamps {
+		line = entry.Timestamp.Format(jsonmessage.RFC3339NanoFixed) + " " + line
+	}
+	o.consumer.Log(entry.Container, entry.Service, line)
+}
+
+type orderedLogEntry struct {
+	api.LogEntry
+	received time.Time
+	sequence int
+}
+
+// logEntryHeap implements heap.Interface, sorting entries by timestamp then by reception order
+type logEntryHeap []orderedLogEntry
+
+func (h logEntryHeap) Len() int {
+	return len(h)
+}
+
+func (h logEntryHeap) Less(i, j int) bool {
+	if h[i].Timestamp.Equal(h[j].Timestamp) {
+		return h[i].sequence < h[j].sequence
+	}
+	return h[i].Timestamp.Before(h[j].Timestamp)
+}
+
+func (h logEntryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *logEntryHeap) Push(x interface{}) {
+	*h = append(*h, x.(orderedLogEntry))
+}
+
+func (h *logEntryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
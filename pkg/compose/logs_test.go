@@ -0,0 +1,153 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	containerType "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestLogsStructuredAndFiltered(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	ctx := context.Background()
+	args := filters.NewArgs(projectFilter(strings.ToLower(testProject)))
+	args.Add("label", "com.docker.compose.oneoff=False")
+	c1 := testContainer("service1", "123", false)
+	c1.Names = []string{"/testproject-service1-2"}
+	c1.Labels[compose.ContainerNumberLabel] = "2"
+	api.EXPECT().ContainerList(ctx, moby.ContainerListOptions{Filters: args, All: true}).Return([]moby.Container{c1}, nil)
+	api.EXPECT().ContainerInspect(anyCancellableContext(), "123").Return(moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{ID: "123"},
+		Config:            &containerType.Config{},
+	}, nil)
+
+	stream := &bytes.Buffer{}
+	stdout := stdcopy.NewStdWriter(stream, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(stream, stdcopy.Stderr)
+	_, _ = stdout.Write([]byte("2022-01-10T12:00:00.000000001Z {\"level\":\"debug\",\"msg\":\"ping\"}\n"))
+	_, _ = stderr.Write([]byte("2022-01-10T12:00:01Z {\"level\":\"error\",\"msg\":\"boom\"}\n"))
+	_, _ = stdout.Write([]byte("2022-01-10T12:00:02Z plain text\n"))
+	_, _ = stdout.Write([]byte("2022-01-10T12:00:03Z {\"level\":\"warn\",\"msg\":\"slow\"}\n"))
+	api.EXPECT().ContainerLogs(anyCancellableContext(), "123", moby.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       "all",
+		Timestamps: true,
+	}).Return(ioutil.NopCloser(stream), nil)
+
+	consumer := &testEntryConsumer{}
+	err := tested.Logs(ctx, strings.ToLower(testProject), consumer, compose.LogOptions{
+		Tail:  "all",
+		Level: "warn",
+		Grep:  "boom|plain",
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, consumer.entries, []compose.LogEntry{
+		{
+			Service:   "service1",
+			Container: "testproject-service1-2",
+			Replica:   2,
+			Stream:    "stderr",
+			Timestamp: time.Date(2022, 1, 10, 12, 0, 1, 0, time.UTC),
+			Message:   `{"level":"error","msg":"boom"}`,
+		},
+		{
+			Service:   "service1",
+			Container: "testproject-service1-2",
+			Replica:   2,
+			Stream:    "stdout",
+			Timestamp: time.Date(2022, 1, 10, 12, 0, 2, 0, time.UTC),
+			Message:   "plain text",
+		},
+	})
+}
+
+func TestLogFilterInvalidOptions(t *testing.T) {
+	_, err := newLogFilter(compose.LogOptions{Grep: "("})
+	assert.Assert(t, compose.IsErrParsingFailed(err))
+	_, err = newLogFilter(compose.LogOptions{Level: "verbose"})
+	assert.Assert(t, compose.IsErrParsingFailed(err))
+}
+
+type testEntryConsumer struct {
+	sync.Mutex
+	entries []compose.LogEntry
+}
+
+func (c *testEntryConsumer) LogEntry(entry compose.LogEntry) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries = append(c.entries, entry)
+}
+
+func (c *testEntryConsumer) Log(container, service, message string) {}
+
+func (c *testEntryConsumer) Status(container, msg string) {}
+
+func (c *testEntryConsumer) Register(container string) {}
+
+func TestLogsOrderer(t *testing.T) {
+	consumer := &testLineConsumer{}
+	orderer := newLogsOrderer(consumer, true)
+	t0 := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	orderer.LogEntry(compose.LogEntry{Container: "db-1", Service: "db", Timestamp: t0.Add(2 * time.Second), Message: "third"})
+	orderer.LogEntry(compose.LogEntry{Container: "web-1", Service: "web", Timestamp: t0, Message: "first"})
+	orderer.LogEntry(compose.LogEntry{Container: "db-1", Service: "db", Timestamp: t0.Add(time.Second), Message: "second"})
+	orderer.LogEntry(compose.LogEntry{Container: "web-1", Service: "web", Timestamp: t0.Add(time.Second), Message: "second bis"})
+
+	orderer.flush(time.Now().Add(-time.Minute))
+	assert.Equal(t, len(consumer.lines), 0)
+
+	orderer.flush(time.Time{})
+	assert.DeepEqual(t, consumer.lines, []string{
+		"web-1 2022-01-10T12:00:00.000000000Z first",
+		"db-1 2022-01-10T12:00:01.000000000Z second",
+		"web-1 2022-01-10T12:00:01.000000000Z second bis",
+		"db-1 2022-01-10T12:00:02.000000000Z third",
+	})
+}
+
+type testLineConsumer struct {
+	lines []string
+}
+
+func (c *testLineConsumer) Log(container, service, message string) {
+	c.lines = append(c.lines, container+" "+message)
+}
+
+func (c *testLineConsumer) Status(container, msg string) {}
+
+func (c *testLineConsumer) Register(container string) {}
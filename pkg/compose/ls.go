@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/docker/compose/v2/pkg/api"
 
@@ -28,8 +29,12 @@ import (
 )
 
 func (s *composeService) List(ctx context.Context, opts api.ListOptions) ([]api.Stack, error) {
+	args := filters.NewArgs(hasProjectLabelFilter())
+	for _, l := range opts.Labels {
+		args.Add("label", l)
+	}
 	list, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
-		Filters: filters.NewArgs(hasProjectLabelFilter()),
+		Filters: args,
 		All:     opts.All,
 	})
 	if err != nil {
@@ -46,15 +51,67 @@ func containersToStacks(containers []moby.Container) ([]api.Stack, error) {
 	}
 	var projects []api.Stack
 	for _, project := range keys {
+		containers := containersByLabel[project]
 		projects = append(projects, api.Stack{
-			ID:     project,
-			Name:   project,
-			Status: combinedStatus(containerToState(containersByLabel[project])),
+			ID:          project,
+			Name:        project,
+			Status:      combinedStatus(containerToState(containers)),
+			ConfigFiles: combinedLabel(containers, api.ConfigFilesLabel),
+			WorkingDir:  combinedLabel(containers, api.WorkingDirLabel),
+			Version:     combinedLabel(containers, api.VersionLabel),
+			Services:    containersToServiceStatus(containers),
 		})
 	}
 	return projects, nil
 }
 
+// combinedLabel collects distinct values set by containers for label, sorted and comma-separated
+func combinedLabel(containers []moby.Container, label string) string {
+	var values []string
+	seen := map[string]bool{}
+	for _, c := range containers {
+		v, ok := c.Labels[label]
+		if !ok || seen[v] {
+			continue
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
+// containersToServiceStatus counts running and existing service containers, ignoring one-off containers
+func containersToServiceStatus(containers []moby.Container) []api.ServiceStatus {
+	byService := map[string]*api.ServiceStatus{}
+	var names []string
+	for _, c := range containers {
+		if c.Labels[api.OneoffLabel] == "True" {
+			continue
+		}
+		name, ok := c.Labels[api.ServiceLabel]
+		if !ok {
+			continue
+		}
+		status, ok := byService[name]
+		if !ok {
+			status = &api.ServiceStatus{ID: name, Name: name}
+			byService[name] = status
+			names = append(names, name)
+		}
+		status.Desired++
+		if c.State == ContainerRunning {
+			status.Replicas++
+		}
+	}
+	sort.Strings(names)
+	var services []api.ServiceStatus
+	for _, name := range names {
+		services = append(services, *byService[name])
+	}
+	return services
+}
+
 func containerToState(containers []moby.Container) []string {
 	statuses := []string{}
 	for _, c := range containers {
@@ -28,33 +28,66 @@ import (
 func TestContainersToStacks(t *testing.T) {
 	containers := []moby.Container{
 		{
-			ID:     "service1",
-			State:  "running",
-			Labels: map[string]string{api.ProjectLabel: "project1"},
+			ID:    "service1",
+			State: "running",
+			Labels: map[string]string{
+				api.ProjectLabel:     "project1",
+				api.ServiceLabel:     "web",
+				api.ConfigFilesLabel: "/src/compose.yaml",
+				api.WorkingDirLabel:  "/src",
+				api.VersionLabel:     "2.2.3",
+			},
 		},
 		{
-			ID:     "service2",
-			State:  "running",
-			Labels: map[string]string{api.ProjectLabel: "project1"},
+			ID:    "service2",
+			State: "exited",
+			Labels: map[string]string{
+				api.ProjectLabel:     "project1",
+				api.ServiceLabel:     "web",
+				api.ConfigFilesLabel: "/src/compose.yaml",
+				api.WorkingDirLabel:  "/src",
+				api.VersionLabel:     "2.2.3",
+			},
 		},
 		{
-			ID:     "service3",
-			State:  "running",
-			Labels: map[string]string{api.ProjectLabel: "project2"},
+			ID:    "service3",
+			State: "running",
+			Labels: map[string]string{
+				api.ProjectLabel: "project2",
+				api.ServiceLabel: "db",
+			},
+		},
+		{
+			ID:    "oneoff",
+			State: "running",
+			Labels: map[string]string{
+				api.ProjectLabel: "project2",
+				api.ServiceLabel: "db",
+				api.OneoffLabel:  "True",
+			},
 		},
 	}
 	stacks, err := containersToStacks(containers)
 	assert.NilError(t, err)
 	assert.DeepEqual(t, stacks, []api.Stack{
 		{
-			ID:     "project1",
-			Name:   "project1",
-			Status: "running(2)",
+			ID:          "project1",
+			Name:        "project1",
+			Status:      "exited(1), running(1)",
+			ConfigFiles: "/src/compose.yaml",
+			WorkingDir:  "/src",
+			Version:     "2.2.3",
+			Services: []api.ServiceStatus{
+				{ID: "web", Name: "web", Replicas: 1, Desired: 2},
+			},
 		},
 		{
 			ID:     "project2",
 			Name:   "project2",
-			Status: "running(1)",
+			Status: "running(2)",
+			Services: []api.ServiceStatus{
+				{ID: "db", Name: "db", Replicas: 1, Desired: 1},
+			},
 		},
 	})
 }
@@ -19,8 +19,12 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
 
 	"github.com/sirupsen/logrus"
 )
@@ -28,16 +32,17 @@ import (
 // logPrinter watch application containers an collect their logs
 type logPrinter interface {
 	HandleEvent(event api.ContainerEvent)
-	Run(ctx context.Context, cascadeStop bool, exitCodeFrom string, stopFn func() error) (int, error)
+	Run(ctx context.Context, options api.StartOptions, stopFn func() error) (int, error)
 	Cancel()
 }
 
-// newLogPrinter builds a LogPrinter passing containers logs to LogConsumer
-func newLogPrinter(consumer api.LogConsumer) logPrinter {
+// newLogPrinter builds a LogPrinter passing containers logs to LogConsumer, and reporting exit status to out
+func newLogPrinter(consumer api.LogConsumer, out io.Writer) logPrinter {
 	queue := make(chan api.ContainerEvent)
 	printer := printer{
 		consumer: consumer,
 		queue:    queue,
+		out:      out,
 	}
 	return &printer
 }
@@ -51,6 +56,7 @@ func (p *printer) Cancel() {
 type printer struct {
 	queue    chan api.ContainerEvent
 	consumer api.LogConsumer
+	out      io.Writer
 }
 
 func (p *printer) HandleEvent(event api.ContainerEvent) {
@@ -58,12 +64,21 @@ func (p *printer) HandleEvent(event api.ContainerEvent) {
 }
 
 //nolint:gocyclo
-func (p *printer) Run(ctx context.Context, cascadeStop bool, exitCodeFrom string, stopFn func() error) (int, error) {
+func (p *printer) Run(ctx context.Context, options api.StartOptions, stopFn func() error) (int, error) {
 	var (
 		aborting bool
 		exitCode int
 	)
-	containers := map[string]struct{}{}
+	exitCodeFrom := append([]string{}, options.ExitCodesFrom...)
+	if options.ExitCodeFrom != "" {
+		exitCodeFrom = append(exitCodeFrom, options.ExitCodeFrom)
+	}
+	if len(exitCodeFrom) == 0 {
+		exitCodeFrom = options.AbortAfterExit
+	}
+	hasPolicy := options.CascadeStop || options.CascadeFail || len(options.AbortAfterExit) > 0
+	containers := map[string]string{}
+	exitCodes := map[string]int{}
 	for {
 		select {
 		case <-ctx.Done():
@@ -77,34 +92,40 @@ func (p *printer) Run(ctx context.Context, cascadeStop bool, exitCodeFrom string
 				if _, ok := containers[container]; ok {
 					continue
 				}
-				containers[container] = struct{}{}
+				containers[container] = event.Service
 				p.consumer.Register(container)
 			case api.ContainerEventExit, api.ContainerEventStopped:
 				if !event.Restarting {
 					delete(containers, container)
+					if code, ok := exitCodes[event.Service]; !ok || event.ExitCode > code {
+						exitCodes[event.Service] = event.ExitCode
+					}
 				}
 				if !aborting {
 					p.consumer.Status(container, fmt.Sprintf("exited with code %d", event.ExitCode))
 				}
-				if cascadeStop {
-					if !aborting {
+				if hasPolicy {
+					if !aborting && shouldAbort(options, event, containers, exitCodes) {
 						aborting = true
-						fmt.Println("Aborting on container exit...")
+						fmt.Fprintln(p.out, "Aborting on container exit...")
 						err := stopFn()
 						if err != nil {
 							return 0, err
 						}
+						if len(exitCodeFrom) == 0 {
+							exitCodeFrom = []string{event.Service}
+						}
 					}
-					if exitCodeFrom == "" {
-						exitCodeFrom = event.Service
-					}
-					if exitCodeFrom == event.Service {
+					if utils.StringContains(exitCodeFrom, event.Service) && event.ExitCode > exitCode {
 						logrus.Error(event.ExitCode)
 						exitCode = event.ExitCode
 					}
 				}
 				if len(containers) == 0 {
 					// Last container terminated, done
+					if hasPolicy {
+						p.printExitCodes(exitCodes)
+					}
 					return exitCode, nil
 				}
 			case api.ContainerEventLog:
@@ -115,3 +136,46 @@ func (p *printer) Run(ctx context.Context, cascadeStop bool, exitCodeFrom string
 		}
 	}
 }
+
+// shouldAbort tells if the application has to be stopped according to the exit policy, after a container exited.
+// containers maps the containers still running to their service, exitCodes the services which containers exited
+func shouldAbort(options api.StartOptions, event api.ContainerEvent, containers map[string]string, exitCodes map[string]int) bool {
+	switch {
+	case options.CascadeStop:
+		return true
+	case options.CascadeFail:
+		return event.ExitCode != 0
+	case len(options.AbortAfterExit) > 0:
+		if !utils.StringContains(options.AbortAfterExit, event.Service) {
+			return false
+		}
+		// a service which containers are not attached yet has not completed
+		for _, service := range options.AbortAfterExit {
+			if _, ok := exitCodes[service]; !ok {
+				return false
+			}
+		}
+		for _, service := range containers {
+			if utils.StringContains(options.AbortAfterExit, service) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// printExitCodes prints the final exit code of each service, as the worst exit code of its containers
+func (p *printer) printExitCodes(exitCodes map[string]int) {
+	var services []string
+	for service := range exitCodes {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	w := tabwriter.NewWriter(p.out, 5, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tEXIT CODE")
+	for _, service := range services {
+		fmt.Fprintf(w, "%s\t%d\n", service, exitCodes[service])
+	}
+	w.Flush() //nolint:errcheck
+}
@@ -0,0 +1,174 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+type printerResult struct {
+	exitCode int
+	err      error
+}
+
+func runPrinter(t *testing.T, options api.StartOptions, events []api.ContainerEvent) (int, bool, string) {
+	out := &bytes.Buffer{}
+	p := newLogPrinter(&testLineConsumer{}, out)
+	stopped := false
+	result := make(chan printerResult)
+	go func() {
+		code, err := p.Run(context.Background(), options, func() error {
+			stopped = true
+			return nil
+		})
+		result <- printerResult{exitCode: code, err: err}
+	}()
+	for _, event := range events {
+		p.HandleEvent(event)
+	}
+	r := <-result
+	assert.NilError(t, r.err)
+	return r.exitCode, stopped, out.String()
+}
+
+func attachEvent(container, service string) api.ContainerEvent {
+	return api.ContainerEvent{Type: api.ContainerEventAttach, Container: container, Service: service}
+}
+
+func exitEvent(container, service string, code int) api.ContainerEvent {
+	return api.ContainerEvent{Type: api.ContainerEventExit, Container: container, Service: service, ExitCode: code}
+}
+
+func TestPrinterAbortOnContainerFailure(t *testing.T) {
+	code, stopped, out := runPrinter(t, api.StartOptions{CascadeFail: true}, []api.ContainerEvent{
+		attachEvent("init-1", "init"),
+		attachEvent("db-1", "db"),
+		attachEvent("test-1", "test"),
+		exitEvent("init-1", "init", 0),
+		exitEvent("test-1", "test", 3),
+		exitEvent("db-1", "db", 137),
+	})
+	assert.Equal(t, code, 3)
+	assert.Assert(t, stopped)
+	assert.Equal(t, out, `Aborting on container exit...
+SERVICE   EXIT CODE
+db        137
+init      0
+test      3
+`)
+}
+
+func TestPrinterNoFailure(t *testing.T) {
+	code, stopped, _ := runPrinter(t, api.StartOptions{CascadeFail: true}, []api.ContainerEvent{
+		attachEvent("init-1", "init"),
+		attachEvent("db-1", "db"),
+		exitEvent("init-1", "init", 0),
+		exitEvent("db-1", "db", 0),
+	})
+	assert.Equal(t, code, 0)
+	assert.Assert(t, !stopped)
+}
+
+func TestPrinterAbortAfterExit(t *testing.T) {
+	code, stopped, _ := runPrinter(t, api.StartOptions{AbortAfterExit: []string{"unit", "integration"}}, []api.ContainerEvent{
+		attachEvent("db-1", "db"),
+		attachEvent("unit-1", "unit"),
+		attachEvent("integration-1", "integration"),
+		attachEvent("integration-2", "integration"),
+		exitEvent("unit-1", "unit", 1),
+		exitEvent("integration-1", "integration", 0),
+		exitEvent("db-1", "db", 2),
+		exitEvent("integration-2", "integration", 4),
+	})
+	assert.Equal(t, code, 4)
+	assert.Assert(t, stopped)
+}
+
+func TestPrinterWaitsForAllServicesBeforeAbort(t *testing.T) {
+	out := &bytes.Buffer{}
+	stopped := false
+	p := newLogPrinter(&testLineConsumer{}, out)
+	result := make(chan printerResult)
+	go func() {
+		code, err := p.Run(context.Background(), api.StartOptions{AbortAfterExit: []string{"unit", "integration"}}, func() error {
+			stopped = true
+			return nil
+		})
+		result <- printerResult{exitCode: code, err: err}
+	}()
+	p.HandleEvent(attachEvent("db-1", "db"))
+	p.HandleEvent(attachEvent("unit-1", "unit"))
+	p.HandleEvent(attachEvent("integration-1", "integration"))
+	p.HandleEvent(exitEvent("unit-1", "unit", 0))
+	// printer has processed previous event once next one is received
+	p.HandleEvent(api.ContainerEvent{Type: api.ContainerEventLog, Container: "db-1", Service: "db", Line: "ready"})
+	assert.Assert(t, !stopped)
+	p.HandleEvent(exitEvent("integration-1", "integration", 0))
+	p.HandleEvent(exitEvent("db-1", "db", 137))
+	r := <-result
+	assert.NilError(t, r.err)
+	assert.Assert(t, stopped)
+	assert.Equal(t, r.exitCode, 0)
+}
+
+func TestPrinterWaitsForServicesAttachedLater(t *testing.T) {
+	out := &bytes.Buffer{}
+	stopped := false
+	p := newLogPrinter(&testLineConsumer{}, out)
+	result := make(chan printerResult)
+	go func() {
+		code, err := p.Run(context.Background(), api.StartOptions{AbortAfterExit: []string{"unit", "integration"}}, func() error {
+			stopped = true
+			return nil
+		})
+		result <- printerResult{exitCode: code, err: err}
+	}()
+	p.HandleEvent(attachEvent("db-1", "db"))
+	p.HandleEvent(attachEvent("unit-1", "unit"))
+	p.HandleEvent(exitEvent("unit-1", "unit", 0))
+	// integration containers are attached once unit ones exited
+	p.HandleEvent(attachEvent("integration-1", "integration"))
+	p.HandleEvent(api.ContainerEvent{Type: api.ContainerEventLog, Container: "db-1", Service: "db", Line: "ready"})
+	assert.Assert(t, !stopped)
+	p.HandleEvent(exitEvent("integration-1", "integration", 3))
+	p.HandleEvent(api.ContainerEvent{Type: api.ContainerEventLog, Container: "db-1", Service: "db", Line: "stopping"})
+	assert.Assert(t, stopped)
+	p.HandleEvent(exitEvent("db-1", "db", 137))
+	r := <-result
+	assert.NilError(t, r.err)
+	assert.Equal(t, r.exitCode, 3)
+}
+
+func TestPrinterExitCodeFromServices(t *testing.T) {
+	code, stopped, out := runPrinter(t, api.StartOptions{CascadeStop: true, ExitCodesFrom: []string{"a"}, ExitCodeFrom: "b"}, []api.ContainerEvent{
+		attachEvent("a-1", "a"),
+		attachEvent("b-1", "b"),
+		attachEvent("c-1", "c"),
+		exitEvent("c-1", "c", 5),
+		exitEvent("a-1", "a", 1),
+		exitEvent("b-1", "b", 2),
+	})
+	assert.Equal(t, code, 2)
+	assert.Assert(t, stopped)
+	assert.Assert(t, bytes.Contains([]byte(out), []byte("c         5")))
+}
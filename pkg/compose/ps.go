@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"sort"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -59,8 +60,12 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 			}
 
 			var (
-				health   string
-				exitCode int
+				health     string
+				exitCode   int
+				oomKilled  bool
+				startedAt  time.Time
+				finishedAt time.Time
+				healthLog  []api.HealthcheckResult
 			)
 			if inspect.State != nil {
 				switch inspect.State.Status {
@@ -71,6 +76,19 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 				case "exited", "dead":
 					exitCode = inspect.State.ExitCode
 				}
+				oomKilled = inspect.State.OOMKilled
+				startedAt = parseStateTime(inspect.State.StartedAt)
+				finishedAt = parseStateTime(inspect.State.FinishedAt)
+				if inspect.State.Health != nil {
+					for _, l := range inspect.State.Health.Log {
+						healthLog = append(healthLog, api.HealthcheckResult{
+							Start:    l.Start,
+							End:      l.End,
+							ExitCode: l.ExitCode,
+							Output:   l.Output,
+						})
+					}
+				}
 			}
 
 			summary[i] = api.ContainerSummary{
@@ -82,10 +100,26 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 				State:      container.State,
 				Health:     health,
 				ExitCode:   exitCode,
+				Labels:     container.Labels,
 				Publishers: publishers,
+
+				RestartCount: inspect.RestartCount,
+				OOMKilled:    oomKilled,
+				StartedAt:    startedAt,
+				FinishedAt:   finishedAt,
+				HealthLog:    healthLog,
 			}
 			return nil
 		})
 	}
 	return summary, eg.Wait()
 }
+
+// parseStateTime parses a timestamp from container state, engine reports zero time for events which didn't happen yet
+func parseStateTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
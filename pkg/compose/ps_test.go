@@ -20,6 +20,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"gotest.tools/v3/assert"
@@ -53,15 +54,49 @@ func TestPs(t *testing.T) {
 	containers, err := tested.Ps(ctx, strings.ToLower(testProject), compose.PsOptions{})
 
 	expected := []compose.ContainerSummary{
-		{ID: "123", Name: "123", Project: strings.ToLower(testProject), Service: "service1", State: "running", Health: "healthy", Publishers: nil},
-		{ID: "456", Name: "456", Project: strings.ToLower(testProject), Service: "service1", State: "running", Health: "", Publishers: []compose.PortPublisher{{URL: "localhost", TargetPort: 90,
+		{ID: "123", Name: "123", Project: strings.ToLower(testProject), Service: "service1", State: "running", Health: "healthy", Labels: c1.Labels, Publishers: nil},
+		{ID: "456", Name: "456", Project: strings.ToLower(testProject), Service: "service1", State: "running", Health: "", Labels: c2.Labels, Publishers: []compose.PortPublisher{{URL: "localhost", TargetPort: 90,
 			PublishedPort: 80}}},
-		{ID: "789", Name: "789", Project: strings.ToLower(testProject), Service: "service2", State: "exited", Health: "", ExitCode: 130, Publishers: nil},
+		{ID: "789", Name: "789", Project: strings.ToLower(testProject), Service: "service2", State: "exited", Health: "", ExitCode: 130, Labels: c3.Labels, Publishers: nil},
 	}
 	assert.NilError(t, err)
 	assert.DeepEqual(t, containers, expected)
 }
 
+func TestPsRestartAndHealthHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	ctx := context.Background()
+	args := filters.NewArgs(projectFilter(strings.ToLower(testProject)))
+	args.Add("label", "com.docker.compose.oneoff=False")
+	listOpts := moby.ContainerListOptions{Filters: args, All: true}
+	c1, inspect1 := containerDetails("service1", "123", "running", "unhealthy", 0)
+	probe := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	inspect1.RestartCount = 3
+	inspect1.State.OOMKilled = true
+	inspect1.State.StartedAt = "2022-01-10T11:59:00.5Z"
+	inspect1.State.FinishedAt = "0001-01-01T00:00:00Z"
+	inspect1.State.Health.Log = []*moby.HealthcheckResult{
+		{Start: probe, End: probe.Add(time.Second), ExitCode: 1, Output: "connection refused"},
+	}
+	api.EXPECT().ContainerList(ctx, listOpts).Return([]moby.Container{c1}, nil)
+	api.EXPECT().ContainerInspect(anyCancellableContext(), "123").Return(inspect1, nil)
+
+	containers, err := tested.Ps(ctx, strings.ToLower(testProject), compose.PsOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 1)
+	assert.Equal(t, containers[0].RestartCount, 3)
+	assert.Equal(t, containers[0].OOMKilled, true)
+	assert.Equal(t, containers[0].StartedAt, time.Date(2022, 1, 10, 11, 59, 0, 500000000, time.UTC))
+	assert.Assert(t, containers[0].FinishedAt.IsZero())
+	assert.DeepEqual(t, containers[0].HealthLog, []compose.HealthcheckResult{
+		{Start: probe, End: probe.Add(time.Second), ExitCode: 1, Output: "connection refused"},
+	})
+}
+
 func containerDetails(service string, id string, status string, health string, exitCode int) (moby.Container, moby.ContainerJSON) {
 	container := moby.Container{
 		ID:     id,
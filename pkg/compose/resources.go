@@ -0,0 +1,199 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+func (s *composeService) VolumesList(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error) {
+	list, err := s.apiClient.VolumeList(ctx, filters.NewArgs(projectFilter(projectName)))
+	if err != nil {
+		return nil, err
+	}
+	var summaries []api.ResourceSummary
+	known := map[string]bool{}
+	for _, volume := range list.Volumes {
+		known[volume.Name] = true
+		summaries = append(summaries, api.ResourceSummary{
+			Name:   volume.Name,
+			Driver: volume.Driver,
+			Labels: volume.Labels,
+			Status: volumeStatus(options.Project, volume.Labels[api.VolumeLabel]),
+		})
+	}
+	if options.Project != nil {
+		for _, volume := range options.Project.Volumes {
+			if !volume.External.External || known[volume.Name] {
+				continue
+			}
+			inspected, err := s.apiClient.VolumeInspect(ctx, volume.Name)
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			known[volume.Name] = true
+			summaries = append(summaries, api.ResourceSummary{
+				Name:   inspected.Name,
+				Driver: inspected.Driver,
+				Labels: inspected.Labels,
+				Status: api.ResourceExternal,
+			})
+		}
+	}
+
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+	for i, summary := range summaries {
+		summaries[i].Services, summaries[i].Containers = resourceUsers(containers.filter(mountsVolume(summary.Name)))
+	}
+	sortResources(summaries)
+	return summaries, nil
+}
+
+func (s *composeService) NetworksList(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error) {
+	networks, err := s.apiClient.NetworkList(ctx, moby.NetworkListOptions{Filters: filters.NewArgs(projectFilter(projectName))})
+	if err != nil {
+		return nil, err
+	}
+	var summaries []api.ResourceSummary
+	known := map[string]bool{}
+	for _, network := range networks {
+		known[network.Name] = true
+		summaries = append(summaries, api.ResourceSummary{
+			Name:   network.Name,
+			Driver: network.Driver,
+			Labels: network.Labels,
+			Status: networkStatus(options.Project, network.Labels[api.NetworkLabel]),
+		})
+	}
+	if options.Project != nil {
+		for _, network := range options.Project.Networks {
+			if !network.External.External || known[network.Name] {
+				continue
+			}
+			inspected, err := s.apiClient.NetworkInspect(ctx, network.Name, moby.NetworkInspectOptions{})
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			known[network.Name] = true
+			summaries = append(summaries, api.ResourceSummary{
+				Name:   inspected.Name,
+				Driver: inspected.Driver,
+				Labels: inspected.Labels,
+				Status: api.ResourceExternal,
+			})
+		}
+	}
+
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+	for i, summary := range summaries {
+		summaries[i].Services, summaries[i].Containers = resourceUsers(containers.filter(isConnectedTo(summary.Name)))
+	}
+	sortResources(summaries)
+	return summaries, nil
+}
+
+func volumeStatus(project *types.Project, name string) string {
+	if project == nil {
+		return ""
+	}
+	volume, ok := project.Volumes[name]
+	switch {
+	case !ok:
+		return api.ResourceOrphaned
+	case volume.External.External:
+		return api.ResourceExternal
+	default:
+		return api.ResourceDeclared
+	}
+}
+
+func networkStatus(project *types.Project, name string) string {
+	if project == nil {
+		return ""
+	}
+	network, ok := project.Networks[name]
+	switch {
+	case !ok && name == "default" && usesDefaultNetwork(project):
+		return api.ResourceDeclared
+	case !ok:
+		return api.ResourceOrphaned
+	case network.External.External:
+		return api.ResourceExternal
+	default:
+		return api.ResourceDeclared
+	}
+}
+
+// usesDefaultNetwork tells if a service is implicitly attached to the project default network
+func usesDefaultNetwork(project *types.Project) bool {
+	for _, service := range project.Services {
+		if len(service.Networks) == 0 && service.NetworkMode == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isConnectedTo(network string) containerPredicate {
+	return func(c moby.Container) bool {
+		if c.NetworkSettings == nil {
+			return false
+		}
+		_, ok := c.NetworkSettings.Networks[network]
+		return ok
+	}
+}
+
+// resourceUsers returns the sorted names of services and containers in containers
+func resourceUsers(containers Containers) ([]string, []string) {
+	var services []string
+	for _, c := range containers {
+		service := c.Labels[api.ServiceLabel]
+		if !utils.StringContains(services, service) {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	return services, containers.sorted().names()
+}
+
+func sortResources(summaries []api.ResourceSummary) {
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+}
@@ -0,0 +1,142 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func testResourcesProject() *types.Project {
+	return &types.Project{
+		Name:     strings.ToLower(testProject),
+		Services: types.Services{{Name: "db"}},
+		Volumes: types.Volumes{
+			"data":   {Name: "testproject_data"},
+			"shared": {Name: "shared", External: types.External{External: true}},
+		},
+	}
+}
+
+func testProjectVolumes() volume.VolumeListOKBody {
+	labels := func(name string) map[string]string {
+		return map[string]string{compose.ProjectLabel: strings.ToLower(testProject), compose.VolumeLabel: name}
+	}
+	return volume.VolumeListOKBody{Volumes: []*moby.Volume{
+		{Name: "testproject_data", Driver: "local", Labels: labels("data")},
+		{Name: "testproject_old", Driver: "local", Labels: labels("old")},
+		{Name: "testproject_cache", Driver: "local", Labels: labels("cache")},
+	}}
+}
+
+func TestVolumesList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	db := testContainer("db", "123", false)
+	db.Names = []string{"/testproject-db-1"}
+	db.Mounts = []moby.MountPoint{
+		{Type: mount.TypeVolume, Name: "testproject_data"},
+		{Type: mount.TypeVolume, Name: "shared"},
+	}
+	api.EXPECT().VolumeList(gomock.Any(), filters.NewArgs(projectFilter(strings.ToLower(testProject)))).Return(testProjectVolumes(), nil)
+	api.EXPECT().VolumeInspect(gomock.Any(), "shared").Return(moby.Volume{Name: "shared", Driver: "nfs"}, nil)
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return([]moby.Container{db}, nil)
+
+	volumes, err := tested.VolumesList(context.Background(), strings.ToLower(testProject), compose.ResourcesListOptions{Project: testResourcesProject()})
+	assert.NilError(t, err)
+	assert.Equal(t, len(volumes), 4)
+	expected := []struct {
+		name     string
+		status   string
+		services string
+	}{
+		{"shared", compose.ResourceExternal, "db"},
+		{"testproject_cache", compose.ResourceOrphaned, ""},
+		{"testproject_data", compose.ResourceDeclared, "db"},
+		{"testproject_old", compose.ResourceOrphaned, ""},
+	}
+	for i, v := range volumes {
+		assert.Equal(t, v.Name, expected[i].name)
+		assert.Equal(t, v.Status, expected[i].status)
+		assert.Equal(t, strings.Join(v.Services, ","), expected[i].services)
+	}
+	assert.DeepEqual(t, volumes[2].Containers, []string{"testproject-db-1"})
+}
+
+func TestNetworksList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	db := testContainer("db", "123", false)
+	db.NetworkSettings = &moby.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{"testproject_default": {}}}
+	api.EXPECT().NetworkList(gomock.Any(), moby.NetworkListOptions{Filters: filters.NewArgs(projectFilter(strings.ToLower(testProject)))}).
+		Return([]moby.NetworkResource{
+			{Name: "testproject_default", Driver: "bridge", Labels: map[string]string{compose.NetworkLabel: "default"}},
+			{Name: "testproject_back", Driver: "bridge", Labels: map[string]string{compose.NetworkLabel: "back"}},
+		}, nil)
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return([]moby.Container{db}, nil)
+
+	networks, err := tested.NetworksList(context.Background(), strings.ToLower(testProject), compose.ResourcesListOptions{Project: testResourcesProject()})
+	assert.NilError(t, err)
+	assert.Equal(t, len(networks), 2)
+	assert.Equal(t, networks[0].Name, "testproject_back")
+	assert.Equal(t, networks[0].Status, compose.ResourceOrphaned)
+	assert.Equal(t, networks[1].Name, "testproject_default")
+	assert.Equal(t, networks[1].Status, compose.ResourceDeclared)
+	assert.DeepEqual(t, networks[1].Services, []string{"db"})
+
+	api.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return([]moby.NetworkResource{{Name: "testproject_default"}}, nil)
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return(nil, nil)
+	networks, err = tested.NetworksList(context.Background(), strings.ToLower(testProject), compose.ResourcesListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, networks[0].Status, "")
+}
+
+func TestVolumesPrune(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	oneOff := testContainer("db", "456", true)
+	oneOff.Mounts = []moby.MountPoint{{Type: mount.TypeVolume, Name: "testproject_cache"}}
+	api.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(testProjectVolumes(), nil)
+	api.EXPECT().VolumeInspect(gomock.Any(), "shared").Return(moby.Volume{Name: "shared"}, nil)
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return([]moby.Container{oneOff}, nil)
+	api.EXPECT().VolumeRemove(gomock.Any(), "testproject_old", true).Return(nil)
+
+	err := tested.VolumesPrune(context.Background(), testResourcesProject(), compose.VolumesPruneOptions{Force: true})
+	assert.NilError(t, err)
+}
@@ -18,9 +18,14 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v2/pkg/progress"
@@ -43,28 +48,148 @@ func (s *composeService) restart(ctx context.Context, project *types.Project, op
 		options.Services = project.ServiceNames()
 	}
 
-	w := progress.ContextWriter(ctx)
+	// services declaring `restart` on a restarted dependency are restarted as well, even when the project has
+	// been restricted to the selected services and their dependencies
+	project = &types.Project{
+		Name:     project.Name,
+		Services: project.AllServices(),
+	}
+	restarted := append([]string{}, options.Services...)
+	for _, service := range options.Services {
+		for _, dependent := range getRestartDependents(project, service) {
+			if !utils.StringContains(restarted, dependent) {
+				restarted = append(restarted, dependent)
+			}
+		}
+	}
+
 	err = InDependencyOrder(ctx, project, func(c context.Context, service string) error {
-		if !utils.StringContains(options.Services, service) {
+		if !utils.StringContains(restarted, service) {
 			return nil
 		}
-		eg, ctx := errgroup.WithContext(ctx)
-		for _, container := range observedState.filter(isService(service)) {
-			container := container
-			eg.Go(func() error {
-				eventName := getContainerProgressName(container)
-				w.Event(progress.RestartingEvent(eventName))
-				err := s.apiClient.ContainerRestart(ctx, container.ID, options.Timeout)
-				if err == nil {
-					w.Event(progress.StartedEvent(eventName))
-				}
+		if !utils.StringContains(options.Services, service) {
+			if err := s.waitRestartedDependencies(ctx, project, service, restarted); err != nil {
 				return err
-			})
+			}
 		}
-		return eg.Wait()
+		containers := observedState.filter(isService(service))
+		if options.Rolling {
+			return s.rollingRestart(ctx, service, containers.filter(isNotOneOff).sorted(), options)
+		}
+		return s.restartContainers(ctx, containers, options.Timeout)
 	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (s *composeService) restartContainers(ctx context.Context, containers Containers, timeout *time.Duration) error {
+	w := progress.ContextWriter(ctx)
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, container := range containers {
+		container := container
+		eg.Go(func() error {
+			eventName := getContainerProgressName(container)
+			w.Event(progress.RestartingEvent(eventName))
+			err := s.apiClient.ContainerRestart(ctx, container.ID, timeout)
+			if err == nil {
+				w.Event(progress.StartedEvent(eventName))
+			}
+			return err
+		})
+	}
+	return eg.Wait()
+}
+
+// rollingRestart restarts service containers by batches of MaxUnavailable, waiting for each batch to be running or
+// healthy before the next one is restarted
+func (s *composeService) rollingRestart(ctx context.Context, service string, containers Containers, options api.RestartOptions) error {
+	w := progress.ContextWriter(ctx)
+	batchSize := options.MaxUnavailable
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	for i := 0; i < len(containers); i += batchSize {
+		end := i + batchSize
+		if end > len(containers) {
+			end = len(containers)
+		}
+		batch := containers[i:end]
+		err := s.restartContainers(ctx, batch, options.Timeout)
+		if err == nil {
+			err = s.waitRestarted(ctx, batch)
+		}
+		if err != nil {
+			w.Events(containerEvents(batch, func(name string) progress.Event {
+				return progress.ErrorMessageEvent(name, "Failed to come back")
+			}))
+			report := fmt.Sprintf("rolling restart of service %q aborted", service)
+			if i > 0 {
+				report += fmt.Sprintf(", restarted: %s", strings.Join(containers[:i].names(), ", "))
+			}
+			report += fmt.Sprintf(", failed: %s", strings.Join(batch.names(), ", "))
+			if end < len(containers) {
+				report += fmt.Sprintf(", not restarted: %s", strings.Join(containers[end:].names(), ", "))
+			}
+			return errors.Wrap(err, report)
+		}
+		w.Events(containerEvents(batch, progress.Healthy))
+	}
+	return nil
+}
+
+// waitRestarted waits for a batch of restarted containers to be healthy, or running when they declare no healthcheck
+func (s *composeService) waitRestarted(ctx context.Context, batch Containers) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		ready := true
+		for _, container := range batch {
+			inspected, err := s.apiClient.ContainerInspect(ctx, container.ID)
+			if err != nil {
+				return err
+			}
+			if inspected.State == nil || !inspected.State.Running || inspected.State.Restarting {
+				status := "gone"
+				if inspected.State != nil {
+					status = inspected.State.Status
+				}
+				return fmt.Errorf("container %s is %s", getCanonicalContainerName(container), status)
+			}
+			if inspected.State.Health == nil {
+				continue
+			}
+			switch inspected.State.Health.Status {
+			case moby.Healthy:
+			case moby.Unhealthy:
+				return fmt.Errorf("container %s is unhealthy", getCanonicalContainerName(container))
+			default:
+				ready = false
+			}
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitRestartedDependencies waits for the restarted dependencies of a service to meet their condition again
+func (s *composeService) waitRestartedDependencies(ctx context.Context, project *types.Project, name string, restarted []string) error {
+	service, err := project.GetService(name)
+	if err != nil {
+		return err
+	}
+	dependencies := types.DependsOnConfig{}
+	for dependency, config := range service.DependsOn {
+		if utils.StringContains(restarted, dependency) {
+			dependencies[dependency] = config
+		}
+	}
+	return s.waitDependencies(ctx, project, dependencies)
+}
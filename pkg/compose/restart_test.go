@@ -0,0 +1,219 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func restartDependentsProject() *types.Project {
+	started := types.ServiceDependency{Condition: types.ServiceConditionStarted}
+	return &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: types.Services{
+			{Name: "db"},
+			{
+				Name:       "app",
+				DependsOn:  types.DependsOnConfig{"db": started},
+				Extensions: map[string]interface{}{extDependsOnRestart: []interface{}{"db"}},
+			},
+			{
+				Name:       "worker",
+				DependsOn:  types.DependsOnConfig{"app": started},
+				Extensions: map[string]interface{}{extDependsOnRestart: []interface{}{"app"}},
+			},
+			{
+				Name:      "web",
+				DependsOn: types.DependsOnConfig{"db": started},
+			},
+		},
+	}
+}
+
+func TestGetRestartDependents(t *testing.T) {
+	project := restartDependentsProject()
+	assert.DeepEqual(t, getRestartDependents(project, "db"), []string{"app", "worker"})
+	assert.DeepEqual(t, getRestartDependents(project, "app"), []string{"worker"})
+	assert.Equal(t, len(getRestartDependents(project, "web")), 0)
+}
+
+func TestStopDependentContainersOfRestrictedProject(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	project := restartDependentsProject()
+	// `up db` restricts the project to db, app and worker are disabled but still restart with it
+	assert.NilError(t, project.ForServices([]string{"db"}))
+	assert.DeepEqual(t, getRestartDependents(project, "db"), []string{"app", "worker"})
+
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(
+		[]moby.Container{testContainer("app", "456", false), testContainer("worker", "789", false)}, nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "456", nil).Return(nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "789", nil).Return(nil)
+
+	c := newConvergence([]string{"db"}, nil, &tested)
+	assert.NilError(t, c.stopDependentContainers(context.Background(), project, "db", nil))
+
+	// stopped dependents are enabled, to be started again with the project
+	enableServices(project, c.stoppedDisabled)
+	assert.DeepEqual(t, project.ServiceNames(), []string{"app", "db", "worker"})
+	assert.Equal(t, len(project.DisabledServices), 1)
+	assert.Equal(t, project.DisabledServices[0].Name, "web")
+}
+
+func TestRestartDependents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	project := restartDependentsProject()
+	// restricting to db moves its dependents to disabled services
+	assert.NilError(t, project.ForServices([]string{"db"}))
+
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return(
+		[]moby.Container{
+			testContainer("db", "123", false),
+			testContainer("app", "456", false),
+			testContainer("worker", "789", false),
+			testContainer("web", "abc", false),
+		}, nil)
+
+	gomock.InOrder(
+		api.EXPECT().ContainerRestart(gomock.Any(), "123", nil).Return(nil),
+		api.EXPECT().ContainerRestart(gomock.Any(), "456", nil).Return(nil),
+		api.EXPECT().ContainerRestart(gomock.Any(), "789", nil).Return(nil),
+	)
+
+	err := tested.restart(context.Background(), project, compose.RestartOptions{Services: []string{"db"}})
+	assert.NilError(t, err)
+}
+
+// rollingRestartMocks mocks a web service with 3 replicas: failing never comes back. When unhealthy is set, replicas
+// declare a healthcheck and unhealthy reports an unhealthy status until it is restarted
+func rollingRestartMocks(t *testing.T, failing, unhealthy string) (*[]string, func() error) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	var containers []moby.Container
+	for i, id := range []string{"123", "456", "789"} {
+		c := testContainer("web", id, false)
+		c.Names = []string{fmt.Sprintf("/web-%d", i+1)}
+		containers = append(containers, c)
+	}
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return(containers, nil)
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(containers, nil).AnyTimes()
+
+	var (
+		mutex sync.Mutex
+		calls []string
+	)
+	record := func(call string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls = append(calls, call)
+	}
+	api.EXPECT().ContainerRestart(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(_ context.Context, id string, _ interface{}) error {
+		record("restart " + id)
+		return nil
+	}).AnyTimes()
+	api.EXPECT().ContainerInspect(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (moby.ContainerJSON, error) {
+		record("inspect " + id)
+		state := &moby.ContainerState{Status: "running", Running: true}
+		if id == failing {
+			state = &moby.ContainerState{Status: "exited"}
+		}
+		config := &container.Config{}
+		if unhealthy != "" {
+			config.Healthcheck = &container.HealthConfig{Test: []string{"CMD", "true"}}
+			state.Health = &moby.Health{Status: moby.Healthy}
+			mutex.Lock()
+			if id == unhealthy && indexOf(calls, "restart "+id) < 0 {
+				state.Health.Status = moby.Unhealthy
+			}
+			mutex.Unlock()
+		}
+		return moby.ContainerJSON{
+			ContainerJSONBase: &moby.ContainerJSONBase{ID: id, State: state},
+			Config:            config,
+		}, nil
+	}).AnyTimes()
+
+	project := &types.Project{
+		Name:     strings.ToLower(testProject),
+		Services: types.Services{{Name: "web"}},
+	}
+	return &calls, func() error {
+		return tested.restart(context.Background(), project, compose.RestartOptions{
+			Services:       []string{"web"},
+			Rolling:        true,
+			MaxUnavailable: 2,
+		})
+	}
+}
+
+func indexOf(calls []string, call string) int {
+	for i, c := range calls {
+		if c == call {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRollingRestart(t *testing.T) {
+	calls, restart := rollingRestartMocks(t, "", "")
+	assert.NilError(t, restart())
+
+	last := indexOf(*calls, "restart 789")
+	assert.Assert(t, last > 0)
+	for _, call := range []string{"restart 123", "restart 456", "inspect 123", "inspect 456"} {
+		i := indexOf(*calls, call)
+		assert.Assert(t, i >= 0 && i < last, "%s should happen before the second batch is restarted", call)
+	}
+}
+
+func TestRollingRestartFailure(t *testing.T) {
+	calls, restart := rollingRestartMocks(t, "456", "")
+	err := restart()
+	assert.Error(t, err, `rolling restart of service "web" aborted, failed: web-1, web-2, not restarted: web-3: container web-2 is exited`)
+	assert.Equal(t, indexOf(*calls, "restart 789"), -1)
+}
+
+func TestRollingRestartUnhealthyReplica(t *testing.T) {
+	// the replica restarted by the second batch is unhealthy, which doesn't hold the first batch
+	calls, restart := rollingRestartMocks(t, "", "789")
+	assert.NilError(t, restart())
+	assert.Assert(t, indexOf(*calls, "restart 789") >= 0)
+}
@@ -30,6 +30,10 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/moby/term"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
 )
 
 func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
@@ -160,7 +164,10 @@ func (s *composeService) prepareRun(ctx context.Context, project *types.Project,
 		return "", err
 	}
 	if !opts.NoDeps {
-		if err := s.waitDependencies(ctx, project, service.DependsOn); err != nil {
+		err := progress.Run(ctx, func(ctx context.Context) error {
+			return s.startDependencies(ctx, project, service, opts)
+		})
+		if err != nil {
 			return "", err
 		}
 	}
@@ -179,6 +186,69 @@ func (s *composeService) prepareRun(ctx context.Context, project *types.Project,
 	return containerID, nil
 }
 
+// startDependencies converges the services the one-off container depends on, then waits for them to meet
+// their `depends_on` condition
+func (s *composeService) startDependencies(ctx context.Context, project *types.Project, service types.ServiceConfig, opts api.RunOptions) error {
+	dependencies := *project
+	dependencies.Services = types.Services{}
+	dependencies.DisabledServices = append(types.Services{}, project.DisabledServices...)
+	ancestors := getAncestors(project, service.Name)
+	for _, s := range project.Services {
+		if utils.StringContains(ancestors, s.Name) {
+			dependencies.Services = append(dependencies.Services, s)
+		} else {
+			dependencies.DisabledServices = append(dependencies.DisabledServices, s)
+		}
+	}
+	if len(dependencies.Services) == 0 {
+		return nil
+	}
+
+	err := s.create(ctx, &dependencies, api.CreateOptions{
+		Recreate:             api.RecreateDiverged,
+		RecreateDependencies: api.RecreateDiverged,
+		QuietPull:            opts.QuietPull,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.WaitTimeout)
+		defer cancel()
+	}
+	err = s.start(ctx, &dependencies, api.StartOptions{}, nil)
+	if err == nil {
+		err = s.waitDependencies(ctx, &dependencies, service.DependsOn)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("dependencies of service %q are not ready after %s", service.Name, opts.WaitTimeout)
+	}
+	return err
+}
+
+// getAncestors returns the services the specified service depends on, directly or transitively
+func getAncestors(project *types.Project, name string) []string {
+	var ancestors []string
+	var visit func(name string)
+	visit = func(name string) {
+		service, err := project.GetService(name)
+		if err != nil {
+			return
+		}
+		for _, dependency := range service.GetDependencies() {
+			if utils.StringContains(ancestors, dependency) {
+				continue
+			}
+			ancestors = append(ancestors, dependency)
+			visit(dependency)
+		}
+	}
+	visit(name)
+	return ancestors
+}
+
 func (s *composeService) getEscapeKeyProxy(r io.ReadCloser, isTty bool) (io.ReadCloser, error) {
 	if !isTty {
 		return r, nil
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestGetAncestors(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "db"},
+			{Name: "cache"},
+			{Name: "api", DependsOn: types.DependsOnConfig{"db": {}}, Links: []string{"cache"}},
+			{Name: "migrate", DependsOn: types.DependsOnConfig{"api": {}}},
+			{Name: "web"},
+		},
+	}
+	ancestors := getAncestors(project, "migrate")
+	sort.Strings(ancestors)
+	assert.DeepEqual(t, ancestors, []string{"api", "cache", "db"})
+	assert.Equal(t, len(getAncestors(project, "web")), 0)
+}
+
+func TestWaitDependenciesTimeout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	project := &types.Project{
+		Name:     strings.ToLower(testProject),
+		Services: types.Services{{Name: "db"}},
+	}
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{testContainer("db", "123", false)}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := tested.waitDependencies(ctx, project, types.DependsOnConfig{
+		"db": {Condition: types.ServiceConditionHealthy},
+	})
+	assert.Assert(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,223 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// extContent sets secret or config content inline in the compose model
+	extContent = "x-content"
+	// extEnvironment sets secret or config content from a project environment variable
+	extEnvironment = "x-environment"
+
+	secretsDir = "/run/secrets/"
+	configsDir = "/"
+
+	// default file mode used by swarm for secrets and configs
+	defaultFileObjectMode = 0444
+)
+
+// fileObjectInjection describes a secret or config to be copied into a container
+type fileObjectInjection struct {
+	target  string
+	uid     int
+	gid     int
+	mode    int64
+	content []byte
+}
+
+// needsInjection tells if a service secret or config can't be bind-mounted and must be copied into the container
+func needsInjection(definition types.FileObjectConfig, reference types.FileReferenceConfig) bool {
+	if _, ok := definition.Extensions[extContent]; ok {
+		return true
+	}
+	if _, ok := definition.Extensions[extEnvironment]; ok {
+		return true
+	}
+	return reference.UID != "" || reference.GID != "" || reference.Mode != nil
+}
+
+func fileObjectTarget(reference types.FileReferenceConfig, baseDir string) string {
+	target := reference.Target
+	if target == "" {
+		target = baseDir + reference.Source
+	} else if !isUnixAbs(target) {
+		target = baseDir + target
+	}
+	return target
+}
+
+// getFileObjectContent resolves content of a secret or config from its inline content, environment variable or file
+func getFileObjectContent(p types.Project, name string, definition types.FileObjectConfig) ([]byte, error) {
+	if content, ok := definition.Extensions[extContent]; ok {
+		s, ok := content.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s must be a string", name, extContent)
+		}
+		return []byte(s), nil
+	}
+	if env, ok := definition.Extensions[extEnvironment]; ok {
+		variable, ok := env.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s must be a string", name, extEnvironment)
+		}
+		value, ok := p.Environment[variable]
+		if !ok {
+			return nil, fmt.Errorf("%s: environment variable %q is not set", name, variable)
+		}
+		return []byte(value), nil
+	}
+	return ioutil.ReadFile(definition.File)
+}
+
+func newFileObjectInjection(p types.Project, name string, definition types.FileObjectConfig, reference types.FileReferenceConfig, baseDir string) (fileObjectInjection, error) {
+	injection, err := newInjection(name, reference, baseDir)
+	if err != nil {
+		return injection, err
+	}
+	injection.content, err = getFileObjectContent(p, name, definition)
+	return injection, err
+}
+
+// newExternalSecretInjection fetches an external secret from the configured SecretProvider
+func (s *composeService) newExternalSecretInjection(ctx context.Context, definition types.SecretConfig, reference types.ServiceSecretConfig) (fileObjectInjection, error) {
+	name := "secret " + reference.Source
+	if s.secretProvider == nil {
+		return fileObjectInjection{}, fmt.Errorf("unsupported external secret %s: no secret provider configured", definition.Name)
+	}
+	injection, err := newInjection(name, types.FileReferenceConfig(reference), secretsDir)
+	if err != nil {
+		return injection, err
+	}
+	injection.content, err = s.secretProvider.GetSecret(ctx, definition.Name)
+	if err != nil {
+		return injection, errors.Wrapf(err, "failed to resolve external %s", name)
+	}
+	return injection, nil
+}
+
+// newInjection prepares injection of a secret or config according to the service reference, without content
+func newInjection(name string, reference types.FileReferenceConfig, baseDir string) (fileObjectInjection, error) {
+	injection := fileObjectInjection{
+		target: fileObjectTarget(reference, baseDir),
+		mode:   defaultFileObjectMode,
+	}
+	var err error
+	if reference.UID != "" {
+		injection.uid, err = strconv.Atoi(reference.UID)
+		if err != nil {
+			return injection, fmt.Errorf("%s: invalid uid %q", name, reference.UID)
+		}
+	}
+	if reference.GID != "" {
+		injection.gid, err = strconv.Atoi(reference.GID)
+		if err != nil {
+			return injection, fmt.Errorf("%s: invalid gid %q", name, reference.GID)
+		}
+	}
+	if reference.Mode != nil {
+		injection.mode = int64(*reference.Mode)
+	}
+	return injection, nil
+}
+
+// getFileObjectInjections collects service secrets and configs which have to be copied into the container
+func (s *composeService) getFileObjectInjections(ctx context.Context, p types.Project, service types.ServiceConfig) ([]fileObjectInjection, error) {
+	var injections []fileObjectInjection
+	for _, secret := range service.Secrets {
+		definition := p.Secrets[secret.Source]
+		if definition.External.External {
+			injection, err := s.newExternalSecretInjection(ctx, definition, secret)
+			if err != nil {
+				return nil, err
+			}
+			injections = append(injections, injection)
+			continue
+		}
+		if !needsInjection(types.FileObjectConfig(definition), types.FileReferenceConfig(secret)) {
+			continue
+		}
+		injection, err := newFileObjectInjection(p, "secret "+secret.Source, types.FileObjectConfig(definition), types.FileReferenceConfig(secret), secretsDir)
+		if err != nil {
+			return nil, err
+		}
+		injections = append(injections, injection)
+	}
+	for _, config := range service.Configs {
+		definition := p.Configs[config.Source]
+		if definition.External.External || !needsInjection(types.FileObjectConfig(definition), types.FileReferenceConfig(config)) {
+			continue
+		}
+		injection, err := newFileObjectInjection(p, "config "+config.Source, types.FileObjectConfig(definition), types.FileReferenceConfig(config), configsDir)
+		if err != nil {
+			return nil, err
+		}
+		injections = append(injections, injection)
+	}
+	return injections, nil
+}
+
+// injectFileObjects copies secrets and configs into a created container, before it gets started
+func (s *composeService) injectFileObjects(ctx context.Context, id string, injections []fileObjectInjection) error {
+	for _, injection := range injections {
+		archive, err := injection.archive()
+		if err != nil {
+			return err
+		}
+		err = s.apiClient.CopyToContainer(ctx, id, "/", archive, moby.CopyToContainerOptions{
+			CopyUIDGID: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archive creates a tar archive with a single file, owned and with permissions as requested
+func (i fileObjectInjection) archive() (*bytes.Buffer, error) {
+	b := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(b)
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name:    path.Clean(i.target)[1:],
+		Size:    int64(len(i.content)),
+		Mode:    i.mode,
+		Uid:     i.uid,
+		Gid:     i.gid,
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tarWriter.Write(i.content); err != nil {
+		return nil, err
+	}
+	return b, tarWriter.Close()
+}
@@ -0,0 +1,158 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestFileObjectInjections(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret.txt")
+	assert.NilError(t, ioutil.WriteFile(secretFile, []byte("from file"), 0600))
+	mode := uint32(0400)
+	project := types.Project{
+		Environment: map[string]string{"DB_PASSWORD": "from env"},
+		Secrets: types.Secrets{
+			"file":    {File: secretFile},
+			"env":     {Extensions: map[string]interface{}{extEnvironment: "DB_PASSWORD"}},
+			"content": {Extensions: map[string]interface{}{extContent: "inline"}},
+		},
+		Configs: types.Configs{
+			"nginx": {Extensions: map[string]interface{}{extContent: "server {}"}},
+		},
+	}
+	service := types.ServiceConfig{
+		Name: "test",
+		Secrets: []types.ServiceSecretConfig{
+			{Source: "file"},
+			{Source: "env", Target: "password", UID: "1000", GID: "1001", Mode: &mode},
+			{Source: "content", Target: "/etc/inline"},
+		},
+		Configs: []types.ServiceConfigObjConfig{
+			{Source: "nginx", Target: "/etc/nginx/nginx.conf"},
+		},
+	}
+
+	injections, err := (&composeService{}).getFileObjectInjections(context.Background(), project, service)
+	assert.NilError(t, err)
+	expected := []fileObjectInjection{
+		{target: "/run/secrets/password", uid: 1000, gid: 1001, mode: 0400, content: []byte("from env")},
+		{target: "/etc/inline", mode: 0444, content: []byte("inline")},
+		{target: "/etc/nginx/nginx.conf", mode: 0444, content: []byte("server {}")},
+	}
+	assert.Equal(t, len(injections), len(expected))
+	for i, injection := range injections {
+		assert.Equal(t, injection.target, expected[i].target)
+		assert.Equal(t, injection.uid, expected[i].uid)
+		assert.Equal(t, injection.gid, expected[i].gid)
+		assert.Equal(t, injection.mode, expected[i].mode)
+		assert.Equal(t, string(injection.content), string(expected[i].content))
+	}
+
+	mounts, err := buildContainerSecretMounts(project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(mounts), 1)
+	assert.Equal(t, mounts[0].Target, "/run/secrets/file")
+
+	project.Secrets["file"] = types.SecretConfig{File: secretFile}
+	service.Secrets[0].UID = "1000"
+	injections, err = (&composeService{}).getFileObjectInjections(context.Background(), project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, string(injections[0].content), "from file")
+}
+
+func TestFileObjectInjectionMissingEnvironment(t *testing.T) {
+	project := types.Project{
+		Secrets: types.Secrets{
+			"env": {Extensions: map[string]interface{}{extEnvironment: "UNSET"}},
+		},
+	}
+	service := types.ServiceConfig{
+		Name:    "test",
+		Secrets: []types.ServiceSecretConfig{{Source: "env"}},
+	}
+	_, err := (&composeService{}).getFileObjectInjections(context.Background(), project, service)
+	assert.ErrorContains(t, err, `environment variable "UNSET" is not set`)
+}
+
+type testSecretProvider map[string]string
+
+func (p testSecretProvider) GetSecret(ctx context.Context, name string) ([]byte, error) {
+	content, ok := p[name]
+	if !ok {
+		return nil, errors.Wrapf(api.ErrNotFound, "secret %q", name)
+	}
+	return []byte(content), nil
+}
+
+func TestExternalSecretInjection(t *testing.T) {
+	project := types.Project{
+		Secrets: types.Secrets{
+			"db": {Name: "prod_db_password", External: types.External{External: true}},
+		},
+	}
+	service := types.ServiceConfig{
+		Name:    "test",
+		Secrets: []types.ServiceSecretConfig{{Source: "db", UID: "999"}},
+	}
+
+	_, err := (&composeService{}).getFileObjectInjections(context.Background(), project, service)
+	assert.ErrorContains(t, err, "no secret provider configured")
+
+	s := &composeService{secretProvider: testSecretProvider{"prod_db_password": "s3cr3t"}}
+	injections, err := s.getFileObjectInjections(context.Background(), project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(injections), 1)
+	assert.Equal(t, injections[0].target, "/run/secrets/db")
+	assert.Equal(t, injections[0].uid, 999)
+	assert.Equal(t, string(injections[0].content), "s3cr3t")
+
+	mounts, err := buildContainerSecretMounts(project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(mounts), 0)
+
+	s.secretProvider = testSecretProvider{}
+	_, err = s.getFileObjectInjections(context.Background(), project, service)
+	assert.Assert(t, errors.Is(err, api.ErrNotFound))
+}
+
+func TestFileObjectInjectionArchive(t *testing.T) {
+	injection := fileObjectInjection{target: "/run/secrets/password", uid: 1000, gid: 1001, mode: 0400, content: []byte("secret")}
+	archive, err := injection.archive()
+	assert.NilError(t, err)
+
+	reader := tar.NewReader(archive)
+	header, err := reader.Next()
+	assert.NilError(t, err)
+	assert.Equal(t, header.Name, "run/secrets/password")
+	assert.Equal(t, header.Uid, 1000)
+	assert.Equal(t, header.Gid, 1001)
+	assert.Equal(t, header.Mode, int64(0400))
+	content, err := ioutil.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "secret")
+}
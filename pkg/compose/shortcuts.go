@@ -0,0 +1,368 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/buger/goterm"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli/streams"
+	"github.com/moby/term"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const (
+	shortcutsHint = "[d] detach  [r] restart  [p] pause  [f] filter  [Ctrl+C] stop"
+	// shortcutsMaxPending is the maximum number of log lines kept while output is paused
+	shortcutsMaxPending = 1000
+)
+
+// keyboardShortcuts reads keystrokes while `up` is attached to a terminal. It decorates the log consumer to filter
+// or pause output, with a hint line displayed below logs
+type keyboardShortcuts struct {
+	mutex      sync.Mutex
+	ctx        context.Context
+	service    *composeService
+	project    *types.Project
+	consumer   api.LogConsumer
+	in         *streams.In
+	out        io.Writer
+	width      func() int
+	state      *term.State
+	signals    chan<- os.Signal
+	detached   chan struct{}
+	closed     bool
+	isDetached bool
+	containers map[string]string
+	filter     string
+	paused     bool
+	pending    []func()
+	prompt     string
+	input      string
+	onInput    func(string)
+	status     string
+}
+
+func newKeyboardShortcuts(ctx context.Context, service *composeService, project *types.Project, consumer api.LogConsumer, signals chan<- os.Signal) *keyboardShortcuts {
+	return &keyboardShortcuts{
+		ctx:        ctx,
+		service:    service,
+		project:    project,
+		consumer:   consumer,
+		in:         streams.NewIn(os.Stdin),
+		out:        os.Stdout,
+		width:      goterm.Width,
+		signals:    signals,
+		detached:   make(chan struct{}),
+		containers: map[string]string{},
+	}
+}
+
+// enter sets the terminal in raw mode and starts reading keystrokes
+func (k *keyboardShortcuts) enter() error {
+	state, err := term.SetRawTerminal(k.in.FD())
+	if err != nil {
+		return err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.state = state
+	go k.readKeys()
+	k.draw()
+	return nil
+}
+
+// Close removes the hint line and restores the terminal, logs are then forwarded as-is
+func (k *keyboardShortcuts) Close() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.close()
+}
+
+func (k *keyboardShortcuts) close() {
+	if k.closed {
+		return
+	}
+	k.closed = true
+	fmt.Fprint(k.out, progress.ClearLine)
+	if k.state != nil {
+		term.RestoreTerminal(k.in.FD(), k.state) //nolint:errcheck
+	}
+	for _, log := range k.pending {
+		log()
+	}
+	k.pending = nil
+}
+
+// Detached is closed once user asked to detach from the application
+func (k *keyboardShortcuts) Detached() <-chan struct{} {
+	return k.detached
+}
+
+// Register implements api.LogConsumer
+func (k *keyboardShortcuts) Register(container string) {
+	k.consumer.Register(container)
+}
+
+// Log implements api.LogConsumer
+func (k *keyboardShortcuts) Log(container, service, message string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.containers[container] = service
+	if k.filter != "" && service != k.filter {
+		return
+	}
+	k.print(func() {
+		k.consumer.Log(container, service, message)
+	})
+}
+
+// Status implements api.LogConsumer
+func (k *keyboardShortcuts) Status(container, msg string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if service, ok := k.containers[container]; ok && k.filter != "" && service != k.filter {
+		return
+	}
+	k.print(func() {
+		k.consumer.Status(container, msg)
+	})
+}
+
+func (k *keyboardShortcuts) print(log func()) {
+	switch {
+	case k.isDetached:
+		return
+	case k.closed:
+		log()
+	case k.paused:
+		k.pending = append(k.pending, log)
+		if len(k.pending) > shortcutsMaxPending {
+			k.pending = k.pending[len(k.pending)-shortcutsMaxPending:]
+		}
+		k.draw()
+	default:
+		fmt.Fprint(k.out, progress.ClearLine)
+		log()
+		k.draw()
+	}
+}
+
+// Start implements progress.Writer
+func (k *keyboardShortcuts) Start(context.Context) error {
+	return nil
+}
+
+// Stop implements progress.Writer
+func (k *keyboardShortcuts) Stop() {}
+
+// Event implements progress.Writer, the last event is displayed on the hint line
+func (k *keyboardShortcuts) Event(e progress.Event) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	status := []string{e.ID}
+	for _, text := range []string{e.Text, e.StatusText} {
+		if text != "" {
+			status = append(status, text)
+		}
+	}
+	k.setStatus(strings.Join(status, " "))
+}
+
+// Events implements progress.Writer
+func (k *keyboardShortcuts) Events(events []progress.Event) {
+	for _, e := range events {
+		k.Event(e)
+	}
+}
+
+// TailMsgf implements progress.Writer
+func (k *keyboardShortcuts) TailMsgf(msg string, args ...interface{}) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.setStatus(fmt.Sprintf(msg, args...))
+}
+
+func (k *keyboardShortcuts) setStatus(status string) {
+	k.status = status
+	k.draw()
+}
+
+// draw renders the hint line, or the pending prompt, at cursor position
+func (k *keyboardShortcuts) draw() {
+	if k.closed {
+		return
+	}
+	hint := shortcutsHint
+	if k.prompt != "" {
+		hint = k.prompt + k.input
+	}
+	var status []string
+	if k.paused {
+		status = append(status, fmt.Sprintf("paused (%d lines pending)", len(k.pending)))
+	}
+	if k.filter != "" {
+		status = append(status, fmt.Sprintf("logs of %s", k.filter))
+	}
+	if k.status != "" {
+		status = append(status, k.status)
+	}
+	fmt.Fprint(k.out, progress.ClearLine, progress.HintLine(hint, strings.Join(status, " | "), k.width()))
+}
+
+func (k *keyboardShortcuts) readKeys() {
+	buf := make([]byte, 3)
+	for {
+		n, err := k.in.Read(buf)
+		if err != nil {
+			return
+		}
+		if !k.handleKey(string(buf[:n])) {
+			return
+		}
+	}
+}
+
+// handleKey processes a keystroke, and returns false once keystrokes are not expected anymore
+func (k *keyboardShortcuts) handleKey(key string) bool {
+	k.mutex.Lock()
+	if k.closed {
+		k.mutex.Unlock()
+		return false
+	}
+	action := k.keyAction(key)
+	k.mutex.Unlock()
+	if action != nil {
+		action()
+	}
+	return true
+}
+
+func (k *keyboardShortcuts) keyAction(key string) func() {
+	if key == "\x03" {
+		// let `up` gracefully stop the application, as it does on SIGINT
+		k.close()
+		return func() {
+			select {
+			case k.signals <- syscall.SIGINT:
+			default:
+			}
+		}
+	}
+	if k.prompt != "" {
+		return k.promptKey(key)
+	}
+	switch key {
+	case "d":
+		k.isDetached = true
+		k.pending = nil
+		k.close()
+		close(k.detached)
+	case "p":
+		k.paused = !k.paused
+		if !k.paused {
+			fmt.Fprint(k.out, progress.ClearLine)
+			for _, log := range k.pending {
+				log()
+			}
+			k.pending = nil
+		}
+		k.draw()
+	case "r":
+		k.ask("Restart service: ", k.restart)
+	case "f":
+		k.ask("Show logs of service (empty for all): ", k.setFilter)
+	}
+	return nil
+}
+
+func (k *keyboardShortcuts) ask(prompt string, onInput func(string)) {
+	k.prompt = prompt
+	k.input = ""
+	k.onInput = onInput
+	k.draw()
+}
+
+func (k *keyboardShortcuts) promptKey(key string) func() {
+	switch key {
+	case "\r", "\n":
+		onInput, input := k.onInput, strings.TrimSpace(k.input)
+		k.prompt, k.input, k.onInput = "", "", nil
+		k.draw()
+		return func() {
+			onInput(input)
+		}
+	case "\x1b":
+		k.prompt, k.input, k.onInput = "", "", nil
+	case "\x7f", "\b":
+		if k.input != "" {
+			k.input = k.input[:len(k.input)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= ' ' && key[0] < 0x7f {
+			k.input += key
+		}
+	}
+	k.draw()
+	return nil
+}
+
+func (k *keyboardShortcuts) setFilter(service string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if service != "" && !k.hasService(service) {
+		k.setStatus(fmt.Sprintf("no such service: %s", service))
+		return
+	}
+	k.filter = service
+	k.setStatus("")
+}
+
+func (k *keyboardShortcuts) restart(service string) {
+	if service == "" {
+		return
+	}
+	k.mutex.Lock()
+	if !k.hasService(service) {
+		k.setStatus(fmt.Sprintf("no such service: %s", service))
+		k.mutex.Unlock()
+		return
+	}
+	k.setStatus(fmt.Sprintf("Restarting %s", service))
+	k.mutex.Unlock()
+	go func() {
+		ctx := progress.WithContextWriter(k.ctx, k)
+		err := k.service.Restart(ctx, k.project, api.RestartOptions{Services: []string{service}})
+		if err != nil {
+			k.TailMsgf("%s", err.Error())
+		}
+	}()
+}
+
+func (k *keyboardShortcuts) hasService(service string) bool {
+	_, err := k.project.GetService(service)
+	return err == nil
+}
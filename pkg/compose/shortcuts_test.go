@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func testKeyboardShortcuts(consumer *testLineConsumer, out *bytes.Buffer) *keyboardShortcuts {
+	return &keyboardShortcuts{
+		project: &types.Project{
+			Name:     "test",
+			Services: types.Services{{Name: "web"}, {Name: "db"}},
+		},
+		consumer:   consumer,
+		out:        out,
+		width:      func() int { return 80 },
+		signals:    make(chan os.Signal, 1),
+		detached:   make(chan struct{}),
+		containers: map[string]string{},
+	}
+}
+
+func typeKeys(k *keyboardShortcuts, keys ...string) {
+	for _, key := range keys {
+		k.handleKey(key)
+	}
+}
+
+func TestShortcutsFilterLogs(t *testing.T) {
+	consumer := &testLineConsumer{}
+	out := &bytes.Buffer{}
+	k := testKeyboardShortcuts(consumer, out)
+
+	typeKeys(k, "f", "w", "x", "\x7f", "e", "b", "\r")
+	assert.Equal(t, k.filter, "web")
+	k.Log("web-1", "web", "hello")
+	k.Log("db-1", "db", "ignored")
+	assert.DeepEqual(t, consumer.lines, []string{"web-1 hello"})
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("logs of web")))
+
+	typeKeys(k, "f", "n", "o", "p", "e", "\r")
+	assert.Equal(t, k.filter, "web")
+	assert.Equal(t, k.status, "no such service: nope")
+
+	typeKeys(k, "f", "\r")
+	assert.Equal(t, k.filter, "")
+	k.Log("db-1", "db", "world")
+	assert.DeepEqual(t, consumer.lines, []string{"web-1 hello", "db-1 world"})
+}
+
+func TestShortcutsPauseLogs(t *testing.T) {
+	consumer := &testLineConsumer{}
+	k := testKeyboardShortcuts(consumer, &bytes.Buffer{})
+
+	typeKeys(k, "p")
+	k.Log("web-1", "web", "first")
+	k.Log("web-1", "web", "second")
+	assert.Equal(t, len(consumer.lines), 0)
+	assert.Equal(t, len(k.pending), 2)
+
+	typeKeys(k, "p")
+	k.Log("web-1", "web", "third")
+	assert.DeepEqual(t, consumer.lines, []string{"web-1 first", "web-1 second", "web-1 third"})
+}
+
+func TestShortcutsPromptCancel(t *testing.T) {
+	k := testKeyboardShortcuts(&testLineConsumer{}, &bytes.Buffer{})
+
+	typeKeys(k, "r", "w", "\x1b", "p")
+	assert.Equal(t, k.prompt, "")
+	assert.Equal(t, k.input, "")
+	assert.Assert(t, k.paused)
+}
+
+func TestShortcutsDetach(t *testing.T) {
+	consumer := &testLineConsumer{}
+	k := testKeyboardShortcuts(consumer, &bytes.Buffer{})
+
+	typeKeys(k, "d")
+	_, open := <-k.Detached()
+	assert.Assert(t, !open)
+	k.Log("web-1", "web", "hello")
+	assert.Equal(t, len(consumer.lines), 0)
+	assert.Assert(t, !k.handleKey("p"))
+}
+
+func TestShortcutsInterrupt(t *testing.T) {
+	consumer := &testLineConsumer{}
+	k := testKeyboardShortcuts(consumer, &bytes.Buffer{})
+	signals := make(chan os.Signal, 1)
+	k.signals = signals
+
+	typeKeys(k, "p")
+	k.Log("web-1", "web", "hello")
+	typeKeys(k, "\x03")
+	assert.Equal(t, <-signals, syscall.SIGINT)
+	// pending logs are flushed and further logs forwarded while the application stops
+	k.Log("web-1", "web", "stopping")
+	assert.DeepEqual(t, consumer.lines, []string{"web-1 hello", "web-1 stopping"})
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
 )
 
 func (s *composeService) Start(ctx context.Context, project *types.Project, options api.StartOptions) error {
@@ -40,14 +41,22 @@ func (s *composeService) start(ctx context.Context, project *types.Project, opti
 	}
 
 	eg, ctx := errgroup.WithContext(ctx)
+	var healer *autoheal
 	if listener != nil {
+		var err error
+		healer, err = s.newAutoheal(project, listener)
+		if err != nil {
+			return err
+		}
+
 		attached, err := s.attach(ctx, project, listener, options.AttachTo)
 		if err != nil {
 			return err
 		}
 
 		eg.Go(func() error {
-			return s.watchContainers(context.Background(), project.Name, options.AttachTo, listener, attached, func(container moby.Container) error {
+			defer healer.stop()
+			return s.watchContainers(context.Background(), project.Name, options.AttachTo, listener, attached, healer, func(container moby.Container) error {
 				return s.attachContainer(ctx, container, listener, project)
 			})
 		})
@@ -59,7 +68,7 @@ func (s *composeService) start(ctx context.Context, project *types.Project, opti
 			return err
 		}
 
-		return s.startService(ctx, project, service)
+		return s.startService(ctx, project, service, listener)
 	})
 	if err != nil {
 		return err
@@ -78,13 +87,19 @@ func (s *composeService) start(ctx context.Context, project *types.Project, opti
 		}
 	}
 
+	err = healer.scan(ctx)
+	if err != nil {
+		return err
+	}
+
 	return eg.Wait()
 }
 
 type containerWatchFn func(container moby.Container) error
 
-// watchContainers uses engine events to capture container start/die and notify ContainerEventListener
-func (s *composeService) watchContainers(ctx context.Context, projectName string, services []string, listener api.ContainerEventListener, containers Containers, onStart containerWatchFn) error {
+// watchContainers uses engine events to capture container start/die and notify ContainerEventListener.
+// Events are also passed to healer, if set, so containers it restarts or replaces are not considered terminated
+func (s *composeService) watchContainers(ctx context.Context, projectName string, services []string, listener api.ContainerEventListener, containers Containers, healer *autoheal, onStart containerWatchFn) error {
 	watched := map[string]int{}
 	for _, c := range containers {
 		watched[c.ID] = 0
@@ -92,11 +107,17 @@ func (s *composeService) watchContainers(ctx context.Context, projectName string
 
 	ctx, stop := context.WithCancel(ctx)
 	err := s.Events(ctx, projectName, api.EventsOptions{
-		Services: services,
 		Consumer: func(event api.Event) error {
+			healing := healer.isHealing(event.Container)
+			healer.handle(ctx, event)
+			if len(services) > 0 && !utils.StringContains(services, event.Service) {
+				return nil
+			}
+
 			if event.Status == "destroy" {
 				// This container can't be inspected, because it's gone.
-				// It's already been removed from the watched map.
+				// It's already been removed from the watched map, unless it has been replaced by autoheal.
+				delete(watched, event.Container)
 				return nil
 			}
 
@@ -113,10 +134,14 @@ func (s *composeService) watchContainers(ctx context.Context, projectName string
 
 			if event.Status == "stop" {
 				listener(api.ContainerEvent{
-					Type:      api.ContainerEventStopped,
-					Container: name,
-					Service:   container.Labels[api.ServiceLabel],
+					Type:       api.ContainerEventStopped,
+					Container:  name,
+					Service:    container.Labels[api.ServiceLabel],
+					Restarting: healing,
 				})
+				if healing {
+					return nil
+				}
 
 				delete(watched, container.ID)
 				if len(watched) == 0 {
@@ -130,7 +155,7 @@ func (s *composeService) watchContainers(ctx context.Context, projectName string
 				restarted := watched[container.ID]
 				watched[container.ID] = restarted + 1
 				// Container terminated.
-				willRestart := willContainerRestart(inspected, restarted)
+				willRestart := healing || willContainerRestart(inspected, restarted)
 
 				listener(api.ContainerEvent{
 					Type:       api.ContainerEventExit,
@@ -18,13 +18,20 @@ package compose
 
 import (
 	"context"
+	"time"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
 
 	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 )
 
+// defaultStopTimeout is the grace period applied by engine when none is set on container
+const defaultStopTimeout = 10 * time.Second
+
 func (s *composeService) Stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
 	return progress.Run(ctx, func(ctx context.Context) error {
 		return s.stop(ctx, project, options)
@@ -44,7 +51,39 @@ func (s *composeService) stop(ctx context.Context, project *types.Project, optio
 		return err
 	}
 
+	var limit *semaphore.Weighted
+	if options.Parallel > 0 {
+		limit = semaphore.NewWeighted(int64(options.Parallel))
+	}
 	return InReverseDependencyOrder(ctx, project, func(c context.Context, service string) error {
-		return s.stopContainers(ctx, w, containers.filter(isService(service)), options.Timeout)
+		timeout := getStopTimeout(project, service, options.Timeout)
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, container := range containers.filter(isService(service)) {
+			container := container
+			eg.Go(func() error {
+				if limit != nil {
+					if err := limit.Acquire(ctx, 1); err != nil {
+						return err
+					}
+					defer limit.Release(1)
+				}
+				return s.stopContainers(ctx, w, []moby.Container{container}, timeout)
+			})
+		}
+		return eg.Wait()
 	})
 }
+
+// getStopTimeout returns the timeout to stop service containers: timeout set by user, or the service
+// stop_grace_period. When none is set, engine relies on the one set on container
+func getStopTimeout(project *types.Project, service string, timeout *time.Duration) *time.Duration {
+	if timeout != nil {
+		return timeout
+	}
+	config, err := project.GetService(service)
+	if err != nil || config.StopGracePeriod == nil {
+		return nil
+	}
+	gracePeriod := time.Duration(*config.StopGracePeriod)
+	return &gracePeriod
+}
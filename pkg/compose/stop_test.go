@@ -18,15 +18,19 @@ package compose
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	compose "github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/mocks"
+	"github.com/docker/compose/v2/pkg/progress"
 
 	"github.com/compose-spec/compose-go/types"
 	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/golang/mock/gomock"
 	"gotest.tools/v3/assert"
 )
@@ -61,3 +65,81 @@ func TestStopTimeout(t *testing.T) {
 	})
 	assert.NilError(t, err)
 }
+
+func TestStopGracePeriod(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	running := func(service, id string) moby.Container {
+		c := testContainer(service, id, false)
+		c.Names = []string{"/" + id}
+		c.State = ContainerRunning
+		return c
+	}
+	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt()).Return(
+		[]moby.Container{
+			running("service1", "123"),
+			running("service2", "456"),
+		}, nil)
+
+	gracePeriod := 30 * time.Second
+	stopTimeout := 5
+	gomock.InOrder(
+		api.EXPECT().ContainerStop(gomock.Any(), "456", nil).Return(nil),
+		api.EXPECT().ContainerInspect(gomock.Any(), "456").Return(moby.ContainerJSON{
+			ContainerJSONBase: &moby.ContainerJSONBase{State: &moby.ContainerState{ExitCode: 137}},
+			Config:            &container.Config{StopTimeout: &stopTimeout},
+		}, nil),
+		api.EXPECT().ContainerStop(gomock.Any(), "123", &gracePeriod).Return(nil),
+		api.EXPECT().ContainerInspect(gomock.Any(), "123").Return(moby.ContainerJSON{
+			ContainerJSONBase: &moby.ContainerJSONBase{State: &moby.ContainerState{ExitCode: 0}},
+			Config:            &container.Config{},
+		}, nil),
+	)
+
+	w := &recordingWriter{events: map[string]string{}}
+	duration := types.Duration(gracePeriod)
+	err := tested.Stop(progress.WithContextWriter(context.Background(), w), &types.Project{
+		Name: strings.ToLower(testProject),
+		Services: []types.ServiceConfig{
+			{Name: "service1", StopGracePeriod: &duration},
+			{Name: "service2", DependsOn: types.DependsOnConfig{"service1": {}}},
+		},
+	}, compose.StopOptions{Parallel: 1})
+	assert.NilError(t, err)
+	assert.Equal(t, w.events["Container 123"], "Stopped")
+	assert.Equal(t, w.events["Container 456"], "Killed")
+	assert.DeepEqual(t, w.tail, []string{"Container 456 didn't stop within its 5s grace period and was killed"})
+}
+
+type recordingWriter struct {
+	mutex  sync.Mutex
+	events map[string]string
+	tail   []string
+}
+
+func (w *recordingWriter) Start(context.Context) error {
+	return nil
+}
+
+func (w *recordingWriter) Stop() {}
+
+func (w *recordingWriter) Event(e progress.Event) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.events[e.ID] = e.StatusText
+}
+
+func (w *recordingWriter) Events(events []progress.Event) {
+	for _, e := range events {
+		w.Event(e)
+	}
+}
+
+func (w *recordingWriter) TailMsgf(msg string, args ...interface{}) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.tail = append(w.tail, fmt.Sprintf(msg, args...))
+}
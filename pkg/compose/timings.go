@@ -0,0 +1,194 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+// buildStep is the step recorded for images built by doBuild, as builds don't report progress events
+const buildStep = "Building"
+
+// TimingsReport sums up the steps recorded by timings while running a command on project
+func TimingsReport(project *types.Project, timings *progress.Timings) api.TimingsReport {
+	var report api.TimingsReport
+	// time spent by each service on each step category, per event ID as replicas are processed in parallel
+	spent := map[string]map[string]map[string]time.Duration{}
+	ready := map[string]time.Duration{}
+	for _, timing := range timings.Timings() {
+		if timing.ParentID != "" {
+			// layers are accounted in their image pull
+			continue
+		}
+		end := timing.End.Sub(timings.Start())
+		if end > report.Total {
+			report.Total = end
+		}
+		if resource := getTimingResource(timing.ID); resource != "" {
+			report.Resources = append(report.Resources, api.ResourceTiming{
+				Resource: resource,
+				Step:     timing.Step,
+				Duration: timing.Duration(),
+			})
+			continue
+		}
+		service := getTimingService(project, timing.ID)
+		category := getTimingCategory(timing.Step)
+		if service == "" || category == "" {
+			continue
+		}
+		if spent[service] == nil {
+			spent[service] = map[string]map[string]time.Duration{}
+		}
+		if spent[service][category] == nil {
+			spent[service][category] = map[string]time.Duration{}
+		}
+		spent[service][category][timing.ID] += timing.Duration()
+		if end > ready[service] {
+			ready[service] = end
+		}
+	}
+
+	report.CriticalPath = getCriticalPath(project, ready)
+	for service, categories := range spent {
+		timing := api.ServiceTimings{
+			Service: service,
+			Ready:   ready[service],
+		}
+		for category, durations := range categories {
+			var longest time.Duration
+			for _, d := range durations {
+				if d > longest {
+					longest = d
+				}
+			}
+			switch category {
+			case "pull":
+				timing.Pull = longest
+			case "build":
+				timing.Build = longest
+			case "create":
+				timing.Create = longest
+			case "start":
+				timing.Start = longest
+			case "wait":
+				timing.Wait = longest
+			}
+		}
+		for _, s := range report.CriticalPath {
+			if s == service {
+				timing.Critical = true
+			}
+		}
+		report.Services = append(report.Services, timing)
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		if report.Services[i].Ready == report.Services[j].Ready {
+			return report.Services[i].Service < report.Services[j].Service
+		}
+		return report.Services[i].Ready < report.Services[j].Ready
+	})
+	return report
+}
+
+// getCriticalPath walks the dependency graph back from the last service to get ready, following at each step the
+// dependency which got ready last
+func getCriticalPath(project *types.Project, ready map[string]time.Duration) []string {
+	graph := NewGraph(project.Services, ServiceStopped)
+	var last *Vertex
+	for _, vertex := range graph.Vertices {
+		last = latest(last, vertex, ready)
+	}
+	var path []string
+	for last != nil {
+		path = append([]string{last.Service}, path...)
+		var next *Vertex
+		for _, dependency := range last.GetChildren() {
+			next = latest(next, dependency, ready)
+		}
+		last = next
+	}
+	return path
+}
+
+func latest(current, candidate *Vertex, ready map[string]time.Duration) *Vertex {
+	r, ok := ready[candidate.Service]
+	if !ok {
+		return current
+	}
+	if current == nil || r > ready[current.Service] || r == ready[current.Service] && candidate.Service < current.Service {
+		return candidate
+	}
+	return current
+}
+
+func getTimingResource(id string) string {
+	for _, prefix := range []string{"Network ", "Volume "} {
+		if strings.HasPrefix(id, prefix) {
+			return strings.ToLower(prefix) + strings.Trim(strings.TrimPrefix(id, prefix), `"`)
+		}
+	}
+	return ""
+}
+
+// getTimingService resolves the service an event ID is about: the service name itself when pulling, the image name
+// when building, or a container name
+func getTimingService(project *types.Project, id string) string {
+	name := strings.TrimPrefix(id, "Container ")
+	for _, service := range project.Services {
+		switch {
+		case id == service.Name, id == getImageName(service, project.Name):
+			return service.Name
+		case service.ContainerName != "":
+			if name == service.ContainerName {
+				return service.Name
+			}
+		default:
+			prefix := strings.Join([]string{project.Name, service.Name, ""}, Separator)
+			if strings.HasPrefix(name, prefix) {
+				if _, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); err == nil {
+					return service.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func getTimingCategory(step string) string {
+	switch step {
+	case "Pulling":
+		return "pull"
+	case buildStep:
+		return "build"
+	case "Creating", "Recreate":
+		return "create"
+	case "Starting", "Running post_start hooks", "Restart":
+		return "start"
+	case "Waiting":
+		return "wait"
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+func TestTimingsReport(t *testing.T) {
+	healthy := types.ServiceDependency{Condition: types.ServiceConditionHealthy}
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			{Name: "db", Image: "postgres"},
+			{Name: "cache", Image: "redis", ContainerName: "my-cache"},
+			{Name: "app", Build: &types.BuildConfig{Context: "."}, DependsOn: types.DependsOnConfig{"db": healthy, "cache": healthy}},
+			{Name: "web", Image: "nginx", DependsOn: types.DependsOnConfig{"app": healthy}},
+		},
+	}
+
+	timings := progress.NewTimings()
+	at := func(seconds int) time.Time {
+		return timings.Start().Add(time.Duration(seconds) * time.Second)
+	}
+	timings.Add("Network myproject_default", "Creating", at(0), at(1))
+	timings.Add("db", "Pulling", at(0), at(10))
+	timings.Add("cache", "Pulling", at(0), at(4))
+	timings.Add("myproject_app", buildStep, at(0), at(20))
+	timings.Add("Container myproject-db-1", "Creating", at(20), at(21))
+	timings.Add("Container my-cache", "Creating", at(20), at(21))
+	timings.Add("Container myproject-app-1", "Creating", at(20), at(22))
+	timings.Add("Container myproject-app-2", "Creating", at(20), at(23))
+	timings.Add("Container myproject-web-1", "Creating", at(20), at(21))
+	timings.Add("Container myproject-db-1", "Starting", at(23), at(24))
+	timings.Add("Container my-cache", "Starting", at(23), at(24))
+	timings.Add("Container myproject-db-1", "Waiting", at(24), at(40))
+	timings.Add("Container my-cache", "Waiting", at(24), at(26))
+	timings.Add("Container myproject-app-1", "Starting", at(40), at(41))
+	timings.Add("Container myproject-app-1", "Waiting", at(41), at(45))
+	timings.Add("Container myproject-web-1", "Starting", at(45), at(46))
+
+	report := TimingsReport(project, timings)
+	assert.Equal(t, report.Total, 46*time.Second)
+	assert.DeepEqual(t, report.CriticalPath, []string{"db", "app", "web"})
+	assert.DeepEqual(t, report.Resources, []api.ResourceTiming{
+		{Resource: "network myproject_default", Step: "Creating", Duration: time.Second},
+	})
+	assert.DeepEqual(t, report.Services, []api.ServiceTimings{
+		{Service: "cache", Pull: 4 * time.Second, Create: time.Second, Start: time.Second, Wait: 2 * time.Second, Ready: 26 * time.Second},
+		{Service: "db", Pull: 10 * time.Second, Create: time.Second, Start: time.Second, Wait: 16 * time.Second, Ready: 40 * time.Second, Critical: true},
+		{Service: "app", Build: 20 * time.Second, Create: 3 * time.Second, Start: time.Second, Wait: 4 * time.Second, Ready: 45 * time.Second, Critical: true},
+		{Service: "web", Create: time.Second, Start: time.Second, Ready: 46 * time.Second, Critical: true},
+	})
+}
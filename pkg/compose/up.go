@@ -19,12 +19,14 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/utils"
 
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/cli/cli"
@@ -50,12 +52,29 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 		return err
 	}
 
-	printer := newLogPrinter(options.Start.Attach)
-
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
 
+	consumer := options.Start.Attach
+	var out io.Writer = os.Stdout
+	var detached <-chan struct{}
+	var keyboard *keyboardShortcuts
+	if options.Start.Shortcuts {
+		keyboard = newKeyboardShortcuts(ctx, s, project, consumer, signalChan)
+		if err := keyboard.enter(); err != nil {
+			return err
+		}
+		defer keyboard.Close()
+		consumer = keyboard
+		detached = keyboard.Detached()
+		out = utils.GetCRLFWriter(os.Stdout)
+	}
+	printer := newLogPrinter(consumer, out)
+
 	stopFunc := func() error {
+		if keyboard != nil {
+			keyboard.Close()
+		}
 		ctx := context.Background()
 		return progress.Run(ctx, func(ctx context.Context) error {
 			go func() {
@@ -72,6 +91,9 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 	}
 	go func() {
 		<-signalChan
+		if keyboard != nil {
+			keyboard.Close()
+		}
 		printer.Cancel()
 		fmt.Println("Gracefully stopping... (press Ctrl+C again to force)")
 		stopFunc() // nolint:errcheck
@@ -80,23 +102,35 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 	var exitCode int
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		code, err := printer.Run(context.Background(), options.Start.CascadeStop, options.Start.ExitCodeFrom, stopFunc)
+		code, err := printer.Run(context.Background(), options.Start, stopFunc)
 		exitCode = code
 		return err
 	})
 
-	err = s.start(ctx, project, options.Start, printer.HandleEvent)
-	if err != nil {
-		return err
-	}
-
-	err = eg.Wait()
-	if exitCode != 0 {
-		errMsg := ""
+	done := make(chan error, 1)
+	go func() {
+		err := s.start(ctx, project, options.Start, printer.HandleEvent)
 		if err != nil {
-			errMsg = err.Error()
+			done <- err
+			return
+		}
+
+		err = eg.Wait()
+		if exitCode != 0 {
+			errMsg := ""
+			if err != nil {
+				errMsg = err.Error()
+			}
+			err = cli.StatusError{StatusCode: exitCode, Status: errMsg}
 		}
-		return cli.StatusError{StatusCode: exitCode, Status: errMsg}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-detached:
+		// leave containers running, as `up --detach` does
+		return nil
 	}
-	return err
 }
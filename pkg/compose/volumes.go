@@ -0,0 +1,569 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/docker/compose/v2/pkg/prompt"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+const (
+	volumesManifestEntry     = "manifest.json"
+	volumesArchiveDir        = "volumes"
+	volumeHelperMountPath    = "/volume"
+	defaultVolumeHelperImage = "busybox"
+)
+
+// volumesManifest is stored as first entry of a volumes backup archive
+type volumesManifest struct {
+	Project string           `json:"project"`
+	Volumes []volumeManifest `json:"volumes"`
+}
+
+// volumeManifest describes an archived volume, data being stored under volumes/<Name>/
+type volumeManifest struct {
+	// Name of the volume in the compose model
+	Name string `json:"name"`
+	// Volume is the actual engine volume name
+	Volume     string            `json:"volume"`
+	Driver     string            `json:"driver,omitempty"`
+	DriverOpts map[string]string `json:"driver_opts,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+func (s *composeService) VolumesBackup(ctx context.Context, projectName string, w io.Writer, options api.VolumesBackupOptions) error {
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return s.volumesBackup(ctx, projectName, w, options)
+	})
+}
+
+func (s *composeService) volumesBackup(ctx context.Context, projectName string, w io.Writer, options api.VolumesBackupOptions) error {
+	volumes, err := s.getProjectVolumes(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	manifest := volumesManifest{Project: projectName}
+	for _, name := range options.Volumes {
+		if _, ok := volumes[name]; !ok {
+			return errors.Wrapf(api.ErrNotFound, "no such volume %q in project %q", name, projectName)
+		}
+	}
+	for name, volume := range volumes {
+		if len(options.Volumes) > 0 && !utils.StringContains(options.Volumes, name) {
+			continue
+		}
+		manifest.Volumes = append(manifest.Volumes, volume)
+	}
+	if len(manifest.Volumes) == 0 {
+		return fmt.Errorf("no volume to back up for project %q", projectName)
+	}
+	sort.Slice(manifest.Volumes, func(i, j int) bool {
+		return manifest.Volumes[i].Name < manifest.Volumes[j].Name
+	})
+
+	restart, err := s.stopVolumeUsers(ctx, projectName, manifest.Volumes, options.Stop, options.Timeout)
+	if err != nil {
+		// containers stopped before the failure are started again
+		if restartErr := restart(ctx); restartErr != nil {
+			logrus.Warnf("failed to restart volume users: %v", restartErr)
+		}
+		return err
+	}
+	err = s.writeVolumesArchive(ctx, w, manifest, options.Image)
+	if err := restart(ctx); err != nil {
+		return err
+	}
+	return err
+}
+
+func (s *composeService) writeVolumesArchive(ctx context.Context, w io.Writer, manifest volumesManifest, image string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:     volumesManifestEntry,
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return err
+	}
+
+	progressWriter := progress.ContextWriter(ctx)
+	for _, volume := range manifest.Volumes {
+		eventName := fmt.Sprintf("Volume %q", volume.Volume)
+		progressWriter.Event(progress.NewEvent(eventName, progress.Working, "Backing up"))
+		err := s.withVolumeHelper(ctx, image, volume.Volume, true, nil, func(id string) error {
+			content, _, err := s.apiClient.CopyFromContainer(ctx, id, volumeHelperMountPath)
+			if err != nil {
+				return err
+			}
+			defer content.Close() //nolint:errcheck
+			return rebaseArchive(tar.NewReader(content), tw, path.Base(volumeHelperMountPath), path.Join(volumesArchiveDir, volume.Name))
+		})
+		if err != nil {
+			progressWriter.Event(progress.ErrorEvent(eventName))
+			return err
+		}
+		progressWriter.Event(progress.NewEvent(eventName, progress.Done, "Backed up"))
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (s *composeService) VolumesRestore(ctx context.Context, projectName string, r io.Reader, options api.VolumesRestoreOptions) error {
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return s.volumesRestore(ctx, projectName, r, options)
+	})
+}
+
+func (s *composeService) volumesRestore(ctx context.Context, projectName string, r io.Reader, options api.VolumesRestoreOptions) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(err, "invalid volumes archive")
+	}
+	tr := tar.NewReader(gz)
+	manifest, err := readVolumesManifest(tr)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.getProjectVolumes(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	selected := map[string]volumeManifest{}
+	for _, volume := range manifest.Volumes {
+		if len(options.Volumes) > 0 && !utils.StringContains(options.Volumes, volume.Name) {
+			continue
+		}
+		if actual, ok := existing[volume.Name]; ok {
+			volume.Volume = actual.Volume
+		} else {
+			volume.Volume = fmt.Sprintf("%s_%s", projectName, volume.Name)
+			err := s.createVolume(ctx, types.VolumeConfig{
+				Name:       volume.Volume,
+				Driver:     volume.Driver,
+				DriverOpts: volume.DriverOpts,
+				Labels: types.Labels(volume.Labels).
+					Add(api.ProjectLabel, projectName).
+					Add(api.VolumeLabel, volume.Name).
+					Add(api.VersionLabel, api.ComposeVersion),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		selected[volume.Name] = volume
+	}
+	for _, name := range options.Volumes {
+		if _, ok := selected[name]; !ok {
+			return errors.Wrapf(api.ErrNotFound, "no such volume %q in archive", name)
+		}
+	}
+	var volumes []volumeManifest
+	for _, volume := range selected {
+		volumes = append(volumes, volume)
+	}
+
+	restart, err := s.stopVolumeUsers(ctx, projectName, volumes, options.Stop, options.Timeout)
+	if err != nil {
+		// containers stopped before the failure are started again
+		if restartErr := restart(ctx); restartErr != nil {
+			logrus.Warnf("failed to restart volume users: %v", restartErr)
+		}
+		return err
+	}
+	err = s.readVolumesArchive(ctx, tr, selected, options.Image)
+	if err := restart(ctx); err != nil {
+		return err
+	}
+	return err
+}
+
+func readVolumesManifest(tr *tar.Reader) (volumesManifest, error) {
+	var manifest volumesManifest
+	header, err := tr.Next()
+	if err != nil {
+		return manifest, errors.Wrap(err, "invalid volumes archive")
+	}
+	if header.Name != volumesManifestEntry {
+		return manifest, fmt.Errorf("invalid volumes archive: %s must be the first entry", volumesManifestEntry)
+	}
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return manifest, errors.Wrap(err, "invalid volumes archive manifest")
+	}
+	return manifest, nil
+}
+
+func (s *composeService) readVolumesArchive(ctx context.Context, tr *tar.Reader, volumes map[string]volumeManifest, image string) error {
+	header, err := tr.Next()
+	for err == nil {
+		volume, ok := volumes[archivedVolumeName(header.Name)]
+		if !ok {
+			header, err = tr.Next()
+			continue
+		}
+		header, err = s.restoreVolume(ctx, tr, header, volume, image)
+	}
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// restoreVolume copies archive entries of volume into it, starting with header, and returns the first header of the next volume
+func (s *composeService) restoreVolume(ctx context.Context, tr *tar.Reader, header *tar.Header, volume volumeManifest, image string) (*tar.Header, error) {
+	w := progress.ContextWriter(ctx)
+	eventName := fmt.Sprintf("Volume %q", volume.Volume)
+	w.Event(progress.NewEvent(eventName, progress.Working, "Restoring"))
+	var next *tar.Header
+	// the volume is emptied first, so that files created since the backup don't survive the restore
+	err := s.withVolumeHelper(ctx, image, volume.Volume, false, emptyVolumeCommand, func(id string) error {
+		pr, pw := io.Pipe()
+		copied := make(chan error, 1)
+		go func() {
+			err := s.apiClient.CopyToContainer(ctx, id, "/", pr, moby.CopyToContainerOptions{CopyUIDGID: true})
+			_ = pr.CloseWithError(err)
+			copied <- err
+		}()
+
+		tw := tar.NewWriter(pw)
+		var err error
+		for next = header; err == nil && archivedVolumeName(next.Name) == volume.Name; next, err = tr.Next() {
+			err = rebaseEntry(next, path.Join(volumesArchiveDir, volume.Name), path.Base(volumeHelperMountPath))
+			if err != nil {
+				break
+			}
+			if err = tw.WriteHeader(next); err != nil {
+				break
+			}
+			if _, err = io.Copy(tw, tr); err != nil {
+				break
+			}
+		}
+		if err != nil && err != io.EOF {
+			_ = pw.CloseWithError(err)
+			<-copied
+			return err
+		}
+		if err == io.EOF {
+			next = nil
+		}
+		if err := tw.Close(); err != nil {
+			_ = pw.CloseWithError(err)
+			<-copied
+			return err
+		}
+		_ = pw.Close()
+		return <-copied
+	})
+	if err != nil {
+		w.Event(progress.ErrorEvent(eventName))
+		return nil, err
+	}
+	w.Event(progress.NewEvent(eventName, progress.Done, "Restored"))
+	if next == nil {
+		return nil, io.EOF
+	}
+	return next, nil
+}
+
+// archivedVolumeName returns the compose volume name an archive entry belongs to, or an empty string
+func archivedVolumeName(name string) string {
+	parts := strings.SplitN(strings.TrimPrefix(name, "./"), "/", 3)
+	if len(parts) < 2 || parts[0] != volumesArchiveDir {
+		return ""
+	}
+	return parts[1]
+}
+
+// rebaseArchive copies all entries from tr to tw, replacing oldBase prefix by newBase in entry names
+func rebaseArchive(tr *tar.Reader, tw *tar.Writer, oldBase string, newBase string) error {
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := rebaseEntry(header, oldBase, newBase); err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func rebaseEntry(header *tar.Header, oldBase string, newBase string) error {
+	rebase := func(name string) (string, error) {
+		trimmed := strings.TrimSuffix(name, "/")
+		if trimmed == oldBase {
+			return newBase + strings.TrimPrefix(name, trimmed), nil
+		}
+		if !strings.HasPrefix(name, oldBase+"/") {
+			return "", fmt.Errorf("unexpected archive entry %q outside of %s", name, oldBase)
+		}
+		return newBase + strings.TrimPrefix(name, oldBase), nil
+	}
+	var err error
+	header.Name, err = rebase(header.Name)
+	if err != nil {
+		return err
+	}
+	if header.Typeflag == tar.TypeLink {
+		header.Linkname, err = rebase(header.Linkname)
+	}
+	return err
+}
+
+// getProjectVolumes lists project volumes indexed by their name in the compose model
+func (s *composeService) getProjectVolumes(ctx context.Context, projectName string) (map[string]volumeManifest, error) {
+	list, err := s.apiClient.VolumeList(ctx, filters.NewArgs(projectFilter(projectName)))
+	if err != nil {
+		return nil, err
+	}
+	volumes := map[string]volumeManifest{}
+	for _, volume := range list.Volumes {
+		name := volume.Labels[api.VolumeLabel]
+		if name == "" {
+			continue
+		}
+		volumes[name] = volumeManifest{
+			Name:       name,
+			Volume:     volume.Name,
+			Driver:     volume.Driver,
+			DriverOpts: volume.Options,
+			Labels:     volume.Labels,
+		}
+	}
+	return volumes, nil
+}
+
+// stopVolumeUsers stops running service containers which mount one of the volumes, in reverse dependency order.
+// It returns a function to start them again in dependency order.
+func (s *composeService) stopVolumeUsers(ctx context.Context, projectName string, volumes []volumeManifest, stop bool, timeout *time.Duration) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !stop {
+		return noop, nil
+	}
+	containers, err := s.getContainers(ctx, projectName, oneOffExclude, false)
+	if err != nil {
+		return noop, err
+	}
+	var names []string
+	for _, volume := range volumes {
+		names = append(names, volume.Volume)
+	}
+	users := containers.filter(mountsVolume(names...))
+	if len(users) == 0 {
+		return noop, nil
+	}
+	project, err := s.projectFromLabels(ctx, containers, projectName)
+	if err != nil {
+		return noop, err
+	}
+
+	w := progress.ContextWriter(ctx)
+	restart := func(ctx context.Context) error {
+		return InDependencyOrder(ctx, project, func(c context.Context, service string) error {
+			for _, container := range users.filter(isService(service)) {
+				eventName := getContainerProgressName(container)
+				w.Event(progress.StartingEvent(eventName))
+				if err := s.apiClient.ContainerStart(c, container.ID, moby.ContainerStartOptions{}); err != nil {
+					return err
+				}
+				w.Event(progress.StartedEvent(eventName))
+			}
+			return nil
+		})
+	}
+	err = InReverseDependencyOrder(ctx, project, func(c context.Context, service string) error {
+		return s.stopContainers(c, w, users.filter(isService(service)), timeout)
+	})
+	if err != nil {
+		return restart, err
+	}
+	return restart, nil
+}
+
+func mountsVolume(volumes ...string) containerPredicate {
+	return func(c moby.Container) bool {
+		for _, m := range c.Mounts {
+			if m.Type == mount.TypeVolume && utils.StringContains(volumes, m.Name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// withVolumeHelper creates a container mounting volume, to access its data while fn runs
+// emptyVolumeCommand removes the content of the volume mounted in the helper container, including hidden files
+var emptyVolumeCommand = []string{"sh", "-c", "rm -rf " + volumeHelperMountPath + "/..?* " + volumeHelperMountPath + "/.[!.]* " + volumeHelperMountPath + "/*"}
+
+// withVolumeHelper runs fn with a helper container mounting volume, running cmd to completion first when set
+func (s *composeService) withVolumeHelper(ctx context.Context, image string, volume string, readOnly bool, cmd []string, fn func(id string) error) error {
+	if image == "" {
+		image = defaultVolumeHelperImage
+	}
+	if err := s.ensureHelperImage(ctx, image); err != nil {
+		return err
+	}
+	created, err := s.apiClient.ContainerCreate(ctx, &container.Config{
+		Image: image,
+		Cmd:   cmd,
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{{
+			Type:     mount.TypeVolume,
+			Source:   volume,
+			Target:   volumeHelperMountPath,
+			ReadOnly: readOnly,
+		}},
+	}, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	defer s.apiClient.ContainerRemove(ctx, created.ID, moby.ContainerRemoveOptions{Force: true}) //nolint:errcheck
+	if len(cmd) > 0 {
+		if err := s.runVolumeHelper(ctx, created.ID, cmd); err != nil {
+			return err
+		}
+	}
+	return fn(created.ID)
+}
+
+// runVolumeHelper starts the helper container and waits for its command to complete
+func (s *composeService) runVolumeHelper(ctx context.Context, id string, cmd []string) error {
+	if err := s.apiClient.ContainerStart(ctx, id, moby.ContainerStartOptions{}); err != nil {
+		return err
+	}
+	exitCh, errCh := s.apiClient.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	select {
+	case exit := <-exitCh:
+		if exit.StatusCode != 0 {
+			return errors.Errorf("volume helper command %q failed with exit code %d", strings.Join(cmd, " "), exit.StatusCode)
+		}
+		return nil
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *composeService) ensureHelperImage(ctx context.Context, image string) error {
+	_, _, err := s.apiClient.ImageInspectWithRaw(ctx, image)
+	if err == nil || !errdefs.IsNotFound(err) {
+		return err
+	}
+	stream, err := s.apiClient.ImagePull(ctx, image, moby.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer stream.Close() //nolint:errcheck
+	_, err = io.Copy(ioutil.Discard, stream)
+	return err
+}
+
+func (s *composeService) VolumesPrune(ctx context.Context, project *types.Project, options api.VolumesPruneOptions) error {
+	volumes, err := s.VolumesList(ctx, project.Name, api.ResourcesListOptions{Project: project})
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, volume := range volumes {
+		if volume.Status != api.ResourceOrphaned {
+			continue
+		}
+		if len(volume.Containers) > 0 {
+			logrus.Warnf("orphaned volume %q is used by %s", volume.Name, strings.Join(volume.Containers, ", "))
+			continue
+		}
+		names = append(names, volume.Name)
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No orphaned volumes")
+		return nil
+	}
+	msg := fmt.Sprintf("Going to remove %s", strings.Join(names, ", "))
+	if options.Force || options.DryRun {
+		fmt.Println(msg)
+	} else {
+		confirm, err := prompt.User{}.Confirm(msg, false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+	if options.DryRun {
+		return nil
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, name := range names {
+			name := name
+			eg.Go(func() error {
+				return s.removeVolume(ctx, name, w)
+			})
+		}
+		return eg.Wait()
+	})
+}
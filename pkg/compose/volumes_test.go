@@ -0,0 +1,189 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestVolumesBackupAndRestore(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+	projectName := strings.ToLower(testProject)
+	timeout := 10 * time.Second
+
+	dataVolume := &moby.Volume{
+		Name:    projectName + "_data",
+		Driver:  "local",
+		Options: map[string]string{"type": "tmpfs"},
+		Labels:  map[string]string{compose.ProjectLabel: projectName, compose.VolumeLabel: "data"},
+	}
+	api.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(volume.VolumeListOKBody{Volumes: []*moby.Volume{dataVolume}}, nil).Times(2)
+	user := testContainer("db", "123", false)
+	user.Mounts = []moby.MountPoint{{Type: mount.TypeVolume, Name: projectName + "_data"}}
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{user, testContainer("web", "456", false)}, nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "123", &timeout).Return(nil)
+	api.EXPECT().ImageInspectWithRaw(gomock.Any(), "busybox").Return(moby.ImageInspect{}, nil, errdefs.NotFound(errors.New("no such image")))
+	api.EXPECT().ImagePull(gomock.Any(), "busybox", gomock.Any()).Return(ioutil.NopCloser(strings.NewReader("")), nil)
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+		DoAndReturn(func(_ context.Context, config *container.Config, hostConfig *container.HostConfig, _, _ interface{}, _ string) (container.ContainerCreateCreatedBody, error) {
+			assert.Equal(t, config.Image, "busybox")
+			assert.Equal(t, hostConfig.Mounts[0].Source, projectName+"_data")
+			assert.Equal(t, hostConfig.Mounts[0].ReadOnly, true)
+			return container.ContainerCreateCreatedBody{ID: "helper"}, nil
+		})
+	api.EXPECT().CopyFromContainer(gomock.Any(), "helper", "/volume").
+		Return(testTarArchive(t, "volume/", "", "volume/data.txt", "some data"), moby.ContainerPathStat{}, nil)
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", moby.ContainerRemoveOptions{Force: true}).Return(nil)
+	api.EXPECT().ContainerStart(gomock.Any(), "123", moby.ContainerStartOptions{}).Return(nil)
+
+	archive := bytes.Buffer{}
+	err := tested.VolumesBackup(context.Background(), projectName, &archive, compose.VolumesBackupOptions{Stop: true, Timeout: &timeout})
+	assert.NilError(t, err)
+
+	api.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(volume.VolumeListOKBody{}, nil)
+	api.EXPECT().VolumeCreate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, options volume.VolumeCreateBody) (moby.Volume, error) {
+			assert.Equal(t, options.Name, "restored_data")
+			assert.Equal(t, options.Driver, "local")
+			assert.Equal(t, options.DriverOpts["type"], "tmpfs")
+			assert.Equal(t, options.Labels[compose.ProjectLabel], "restored")
+			assert.Equal(t, options.Labels[compose.VolumeLabel], "data")
+			return moby.Volume{}, nil
+		})
+	api.EXPECT().ImageInspectWithRaw(gomock.Any(), "busybox").Return(moby.ImageInspect{}, nil, nil)
+	api.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+		DoAndReturn(func(_ context.Context, config *container.Config, hostConfig *container.HostConfig, _, _ interface{}, _ string) (container.ContainerCreateCreatedBody, error) {
+			assert.DeepEqual(t, []string(config.Cmd), emptyVolumeCommand)
+			assert.Equal(t, hostConfig.Mounts[0].ReadOnly, false)
+			return container.ContainerCreateCreatedBody{ID: "helper"}, nil
+		})
+	// the volume is emptied before the archive is extracted
+	exited := make(chan container.ContainerWaitOKBody, 1)
+	exited <- container.ContainerWaitOKBody{StatusCode: 0}
+	start := api.EXPECT().ContainerStart(gomock.Any(), "helper", moby.ContainerStartOptions{}).Return(nil)
+	wait := api.EXPECT().ContainerWait(gomock.Any(), "helper", container.WaitConditionNotRunning).
+		Return(exited, make(chan error)).After(start)
+	restored := map[string]string{}
+	api.EXPECT().CopyToContainer(gomock.Any(), "helper", "/", gomock.Any(), moby.CopyToContainerOptions{CopyUIDGID: true}).After(wait).
+		DoAndReturn(func(_ context.Context, _ string, _ string, content io.Reader, _ moby.CopyToContainerOptions) error {
+			tr := tar.NewReader(content)
+			for {
+				header, err := tr.Next()
+				if err == io.EOF {
+					return nil
+				}
+				assert.NilError(t, err)
+				data, err := ioutil.ReadAll(tr)
+				assert.NilError(t, err)
+				restored[header.Name] = string(data)
+			}
+		})
+	api.EXPECT().ContainerRemove(gomock.Any(), "helper", moby.ContainerRemoveOptions{Force: true}).Return(nil)
+
+	err = tested.VolumesRestore(context.Background(), "restored", &archive, compose.VolumesRestoreOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, restored, map[string]string{"volume/": "", "volume/data.txt": "some data"})
+}
+
+func TestVolumesBackupRestartsUsersWhenStopFails(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+	projectName := strings.ToLower(testProject)
+
+	dataVolume := &moby.Volume{
+		Name:   projectName + "_data",
+		Labels: map[string]string{compose.ProjectLabel: projectName, compose.VolumeLabel: "data"},
+	}
+	api.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(volume.VolumeListOKBody{Volumes: []*moby.Volume{dataVolume}}, nil).Times(2)
+	db := testContainer("db", "123", false)
+	db.Mounts = []moby.MountPoint{{Type: mount.TypeVolume, Name: projectName + "_data"}}
+	worker := testContainer("worker", "456", false)
+	worker.Mounts = db.Mounts
+	api.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]moby.Container{db, worker}, nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "123", nil).Return(nil)
+	api.EXPECT().ContainerStop(gomock.Any(), "456", nil).Return(errors.New("cannot stop"))
+	api.EXPECT().ContainerStart(gomock.Any(), "123", moby.ContainerStartOptions{}).Return(nil)
+	api.EXPECT().ContainerStart(gomock.Any(), "456", moby.ContainerStartOptions{}).Return(nil)
+
+	err := tested.VolumesBackup(context.Background(), projectName, &bytes.Buffer{}, compose.VolumesBackupOptions{Stop: true})
+	assert.Error(t, err, "cannot stop")
+}
+
+func TestVolumesRestoreUnknownVolume(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	api := mocks.NewMockAPIClient(mockCtrl)
+	tested.apiClient = api
+
+	archive := bytes.Buffer{}
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	manifest := `{"project":"test","volumes":[]}`
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: volumesManifestEntry, Mode: 0644, Size: int64(len(manifest))}))
+	_, err := tw.Write([]byte(manifest))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gz.Close())
+
+	api.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(volume.VolumeListOKBody{}, nil)
+	err = tested.VolumesRestore(context.Background(), "test", &archive, compose.VolumesRestoreOptions{Volumes: []string{"data"}})
+	assert.Assert(t, errors.Is(err, compose.ErrNotFound))
+}
+
+// testTarArchive creates a tar archive from name and content pairs, names ending with a slash being directories
+func testTarArchive(t *testing.T, entries ...string) io.ReadCloser {
+	buf := bytes.Buffer{}
+	tw := tar.NewWriter(&buf)
+	for i := 0; i+1 < len(entries); i += 2 {
+		name, content := entries[i], entries[i+1]
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(name, "/") {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		}
+		assert.NilError(t, tw.WriteHeader(header))
+		_, err := tw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return ioutil.NopCloser(&buf)
+}
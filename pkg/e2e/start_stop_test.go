@@ -53,6 +53,15 @@ func TestStartStop(t *testing.T) {
 		testify.Regexp(t, getServiceRegx("another", "running"), res.Stdout())
 	})
 
+	t.Run("ls filters", func(t *testing.T) {
+		res := c.RunDockerComposeCmd("ls", "--filter", "name="+projectName, "--format", "json")
+		assert.Assert(t, strings.Contains(res.Stdout(), `"Name":"e2e-start-stop"`), res.Stdout())
+		assert.Assert(t, strings.Contains(res.Stdout(), `"Name":"simple","Running":1,"Desired":1`), res.Stdout())
+
+		res = c.RunDockerComposeCmd("ls", "--filter", "label=com.docker.compose.project.working_dir=/nowhere", "-q")
+		assert.Assert(t, !strings.Contains(res.Stdout(), projectName), res.Stdout())
+	})
+
 	t.Run("stop project", func(t *testing.T) {
 		c.RunDockerComposeCmd("-f", "./fixtures/start-stop/compose.yaml", "--project-name", projectName, "stop")
 
@@ -0,0 +1,155 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timing is the time spent on a step of a progress event, from the first event reporting it working until it's done
+type Timing struct {
+	ID string
+	// ParentID is set for sub-tasks, like image layers pulled for a service
+	ParentID string
+	Step     string
+	Start    time.Time
+	End      time.Time
+	Failed   bool
+}
+
+// Duration returns the time spent on the step
+func (t Timing) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// Timings records the steps of progress events reported to writers
+type Timings struct {
+	mtx     sync.Mutex
+	now     func() time.Time
+	start   time.Time
+	running map[string]*Timing
+	done    []Timing
+}
+
+// NewTimings returns a recorder for steps starting from now
+func NewTimings() *Timings {
+	return &Timings{
+		now:     time.Now,
+		start:   time.Now(),
+		running: map[string]*Timing{},
+	}
+}
+
+type timingsKey struct{}
+
+// WithTimings adds the timings recorder to the context, progress events are then recorded
+func WithTimings(ctx context.Context, timings *Timings) context.Context {
+	return context.WithValue(ctx, timingsKey{}, timings)
+}
+
+// ContextTimings returns the timings recorder from the context, or nil
+func ContextTimings(ctx context.Context) *Timings {
+	timings, _ := ctx.Value(timingsKey{}).(*Timings)
+	return timings
+}
+
+// Start returns the time the recorder has been created at
+func (t *Timings) Start() time.Time {
+	return t.start
+}
+
+// Record updates the steps of the event ID. A working event starts a step, named by the event status text, and
+// a done or error event ends it
+func (t *Timings) Record(e Event) {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	step := e.StatusText
+	if step == "" {
+		step = e.Text
+	}
+	now := t.now()
+	// sub-tasks IDs, like layers, are only unique for their parent
+	key := e.ParentID + "/" + e.ID
+	running, ok := t.running[key]
+	switch e.Status {
+	case Working:
+		if ok && running.Step == step {
+			return
+		}
+		if ok {
+			t.end(running, now, false)
+		}
+		t.running[key] = &Timing{ID: e.ID, ParentID: e.ParentID, Step: step, Start: now}
+	case Done, Error:
+		if ok {
+			t.end(running, now, e.Status == Error)
+		}
+	}
+}
+
+// Add records a step which is not reported by progress events
+func (t *Timings) Add(id, step string, start, end time.Time) {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.done = append(t.done, Timing{ID: id, Step: step, Start: start, End: end})
+}
+
+func (t *Timings) end(running *Timing, end time.Time, failed bool) {
+	running.End = end
+	running.Failed = failed
+	t.done = append(t.done, *running)
+	delete(t.running, running.ParentID+"/"+running.ID)
+}
+
+// Timings returns the completed steps ordered by start time
+func (t *Timings) Timings() []Timing {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	timings := make([]Timing, len(t.done))
+	copy(timings, t.done)
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Start.Before(timings[j].Start)
+	})
+	return timings
+}
+
+// timingsWriter records events before they get written
+type timingsWriter struct {
+	Writer
+	timings *Timings
+}
+
+func (w *timingsWriter) Event(e Event) {
+	w.timings.Record(e)
+	w.Writer.Event(e)
+}
+
+func (w *timingsWriter) Events(events []Event) {
+	for _, e := range events {
+		w.timings.Record(e)
+	}
+	w.Writer.Events(events)
+}
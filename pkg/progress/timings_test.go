@@ -0,0 +1,68 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTimingsRecordSteps(t *testing.T) {
+	timings := NewTimings()
+	clock := timings.Start()
+	timings.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	ctx := WithTimings(context.TODO(), timings)
+	w := ContextWriter(ctx)
+	w.Event(CreatingEvent("Container web-1"))
+	w.Event(CreatedEvent("Container web-1"))
+	w.Event(Event{ID: "db", Status: Working, Text: "Pulling"})
+	w.Event(Event{ID: "a1b2", ParentID: "db", Status: Working, Text: "Downloading"})
+	w.Event(Event{ID: "db", Status: Working, Text: "Pulling"})
+	w.Event(Event{ID: "a1b2", ParentID: "db", Status: Done, Text: "Pull complete"})
+	w.Event(Event{ID: "db", Status: Error, Text: "Error"})
+	w.Events([]Event{StartingEvent("Container web-1")})
+	w.Event(NewEvent("Container web-1", Working, "Running post_start hooks"))
+	w.Event(StartedEvent("Container web-1"))
+	// events done at once have no step
+	w.Event(RunningEvent("Container db-1"))
+
+	start := timings.Start()
+	second := func(n int) time.Time {
+		return start.Add(time.Duration(n) * time.Second)
+	}
+	assert.DeepEqual(t, timings.Timings(), []Timing{
+		{ID: "Container web-1", Step: "Creating", Start: second(1), End: second(2)},
+		{ID: "db", Step: "Pulling", Start: second(3), End: second(7), Failed: true},
+		{ID: "a1b2", ParentID: "db", Step: "Downloading", Start: second(4), End: second(6)},
+		{ID: "Container web-1", Step: "Starting", Start: second(8), End: second(9)},
+		{ID: "Container web-1", Step: "Running post_start hooks", Start: second(9), End: second(10)},
+	})
+}
+
+func TestTimingsWithoutRecorder(t *testing.T) {
+	var timings *Timings
+	timings.Record(StartingEvent("Container web-1"))
+	timings.Add("web", "Building", time.Now(), time.Now())
+	assert.Assert(t, ContextTimings(context.TODO()) == nil)
+}
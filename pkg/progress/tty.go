@@ -240,3 +240,23 @@ func numDone(events map[string]Event) int {
 func align(l, r string, w int) string {
 	return fmt.Sprintf("%-[2]*[1]s %[3]s", l, w-len(r)-1, r)
 }
+
+// ClearLine moves the cursor back to the beginning of the current line and erases it
+var ClearLine = aec.Column(0).With(aec.EraseLine(aec.EraseModes.All)).String()
+
+// HintLine formats hint as a faint line fitting the terminal width, with status aligned on the right
+func HintLine(hint, status string, terminalWidth int) string {
+	line := hint
+	if status != "" {
+		if terminalWidth > len(hint)+len(status)+1 {
+			line = align(hint, status, terminalWidth-1)
+		} else {
+			line = hint + " " + status
+		}
+	}
+	// leave the last column empty so the line doesn't wrap
+	if terminalWidth > 0 && len(line) >= terminalWidth {
+		line = line[:terminalWidth-1]
+	}
+	return aec.Apply(line, aec.Faint)
+}
@@ -103,3 +103,14 @@ func TestErrorEvent(t *testing.T) {
 	assert.Assert(t, ok)
 	assert.Assert(t, event.endTime.After(time.Now().Add(-10*time.Second)))
 }
+
+func TestHintLine(t *testing.T) {
+	out := HintLine("[d] detach", "", 50)
+	assert.Equal(t, out, "\x1b[2m[d] detach\x1b[0m")
+
+	out = HintLine("[d] detach", "paused", 30)
+	assert.Equal(t, out, "\x1b[2m[d] detach             paused\x1b[0m")
+
+	out = HintLine("[d] detach", "paused", 12)
+	assert.Equal(t, out, "\x1b[2m[d] detach \x1b[0m")
+}
@@ -46,6 +46,9 @@ func WithContextWriter(ctx context.Context, writer Writer) context.Context {
 func ContextWriter(ctx context.Context) Writer {
 	s, ok := ctx.Value(writerKey{}).(Writer)
 	if !ok {
+		if timings := ContextTimings(ctx); timings != nil {
+			return &timingsWriter{Writer: &noopWriter{}, timings: timings}
+		}
 		return &noopWriter{}
 	}
 	return s
@@ -65,12 +68,19 @@ func Run(ctx context.Context, pf progressFunc) error {
 
 // RunWithStatus will run a writer and the progress function in parallel and return a status
 func RunWithStatus(ctx context.Context, pf progressFuncWithStatus) (string, error) {
+	if _, ok := ctx.Value(writerKey{}).(Writer); ok {
+		// events are reported to the writer already set by the caller
+		return pf(ctx)
+	}
 	eg, _ := errgroup.WithContext(ctx)
 	w, err := NewWriter(os.Stderr)
 	var result string
 	if err != nil {
 		return "", err
 	}
+	if timings := ContextTimings(ctx); timings != nil {
+		w = &timingsWriter{Writer: w, timings: timings}
+	}
 	eg.Go(func() error {
 		return w.Start(context.Background())
 	})
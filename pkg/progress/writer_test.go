@@ -29,3 +29,41 @@ func TestNoopWriter(t *testing.T) {
 
 	assert.Equal(t, writer, &noopWriter{})
 }
+
+type recordingWriter struct {
+	noopWriter
+	events  []Event
+	stopped bool
+}
+
+func (w *recordingWriter) Event(e Event) {
+	w.events = append(w.events, e)
+}
+
+func (w *recordingWriter) Stop() {
+	w.stopped = true
+}
+
+func TestRunWithContextWriter(t *testing.T) {
+	w := &recordingWriter{}
+	ctx := WithContextWriter(context.TODO(), w)
+	err := Run(ctx, func(ctx context.Context) error {
+		ContextWriter(ctx).Event(StartedEvent("test"))
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(w.events), 1)
+}
+
+func TestRunWithStatusKeepsContextWriter(t *testing.T) {
+	w := &recordingWriter{}
+	ctx := WithContextWriter(context.TODO(), w)
+	status, err := RunWithStatus(ctx, func(ctx context.Context) (string, error) {
+		assert.Equal(t, ContextWriter(ctx), Writer(w))
+		return "done", nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, status, "done")
+	// the writer belongs to the caller, which stops it once done with it
+	assert.Check(t, !w.stopped)
+}
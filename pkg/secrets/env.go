@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package secrets
+
+import (
+	"os"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+const (
+	// HelperEnvVar sets the secret helper program to resolve external secrets
+	HelperEnvVar = "COMPOSE_SECRETS_HELPER"
+	// StoreEnvVar sets the path of a local encrypted secrets store to resolve external secrets
+	StoreEnvVar = "COMPOSE_SECRETS_STORE"
+	// PassphraseEnvVar sets the passphrase of the local secrets store
+	PassphraseEnvVar = "COMPOSE_SECRETS_PASSPHRASE"
+)
+
+// ProviderFromEnv returns the SecretProvider configured by environment variables, or nil if none is set
+func ProviderFromEnv() api.SecretProvider {
+	if helper, ok := os.LookupEnv(HelperEnvVar); ok && helper != "" {
+		return NewHelper(helper)
+	}
+	if store, ok := os.LookupEnv(StoreEnvVar); ok && store != "" {
+		return NewFileStore(store, os.Getenv(PassphraseEnvVar))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// errHelperNotFound is the message a helper prints to report an unknown secret, as docker credential helpers do
+const errHelperNotFound = "secret not found"
+
+// Helper is a SecretProvider delegating to an external program, following docker credential helpers conventions:
+// the program is ran with `get` argument, secret name on stdin, and prints secret content on stdout
+type Helper struct {
+	program string
+}
+
+var _ api.SecretProvider = &Helper{}
+
+// NewHelper creates a Helper running program, looked up in PATH
+func NewHelper(program string) *Helper {
+	return &Helper{
+		program: program,
+	}
+}
+
+// GetSecret implements api.SecretProvider
+func (h *Helper) GetSecret(ctx context.Context, name string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, h.program, "get")
+	cmd.Stdin = strings.NewReader(name)
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stdout.String() + stderr.String())
+		if strings.Contains(message, errHelperNotFound) {
+			return nil, errors.Wrapf(api.ErrNotFound, "secret %q", name)
+		}
+		return nil, errors.Wrapf(err, "secret helper %s failed to get secret %q: %s", h.program, name, message)
+	}
+	return stdout.Bytes(), nil
+}
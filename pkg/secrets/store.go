@@ -0,0 +1,166 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// FileStore is a SecretProvider reading secrets from a local file, encrypted with a passphrase
+type FileStore struct {
+	path       string
+	passphrase []byte
+}
+
+var _ api.SecretProvider = &FileStore{}
+
+// NewFileStore creates a FileStore for the secrets file at path
+func NewFileStore(path string, passphrase string) *FileStore {
+	return &FileStore{
+		path:       path,
+		passphrase: []byte(passphrase),
+	}
+}
+
+// storeFile is the on-disk format of a FileStore
+type storeFile struct {
+	// Salt used to derive encryption key from passphrase
+	Salt []byte `json:"salt"`
+	// Secrets hold each secret encrypted with AES-GCM, prefixed by its nonce
+	Secrets map[string][]byte `json:"secrets"`
+}
+
+// GetSecret implements api.SecretProvider
+func (s *FileStore) GetSecret(ctx context.Context, name string) ([]byte, error) {
+	store, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := s.cipher(store.Salt)
+	if err != nil {
+		return nil, err
+	}
+	sealed, ok := store.Secrets[name]
+	if !ok {
+		return nil, errors.Wrapf(api.ErrNotFound, "secret %q", name)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.Errorf("secret %q is corrupted", name)
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	content, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt secret %q, check passphrase", name)
+	}
+	return content, nil
+}
+
+// SetSecret encrypts and stores content as the named secret, creating the store file if missing
+func (s *FileStore) SetSecret(name string, content []byte) error {
+	store, err := s.load()
+	if os.IsNotExist(errors.Cause(err)) {
+		store = storeFile{Secrets: map[string][]byte{}}
+		store.Salt = make([]byte, 16)
+		if _, err := io.ReadFull(rand.Reader, store.Salt); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	gcm, err := s.cipher(store.Salt)
+	if err != nil {
+		return err
+	}
+	if err := checkPassphrase(gcm, store); err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	store.Secrets[name] = gcm.Seal(nonce, nonce, content, []byte(name))
+	b, err := json.Marshal(store)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, b, 0600)
+}
+
+// checkPassphrase makes sure the store secrets are encrypted with the key of gcm, so that all of them can be decrypted
+// with the same passphrase
+func checkPassphrase(gcm cipher.AEAD, store storeFile) error {
+	var names []string
+	for name := range store.Secrets {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	sealed := store.Secrets[names[0]]
+	if len(sealed) < gcm.NonceSize() {
+		return errors.Errorf("secret %q is corrupted", names[0])
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	if _, err := gcm.Open(nil, nonce, ciphertext, []byte(names[0])); err != nil {
+		return errors.New("passphrase doesn't match the one the stored secrets are encrypted with")
+	}
+	return nil
+}
+
+func (s *FileStore) load() (storeFile, error) {
+	var store storeFile
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return store, errors.Wrap(err, "failed to read secrets store")
+	}
+	if err := json.Unmarshal(b, &store); err != nil {
+		return store, errors.Wrapf(err, "invalid secrets store %s", s.path)
+	}
+	if store.Secrets == nil {
+		store.Secrets = map[string][]byte{}
+	}
+	return store, nil
+}
+
+func (s *FileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if len(s.passphrase) == 0 {
+		return nil, errors.New("a passphrase is required to encrypt secrets")
+	}
+	key, err := scrypt.Key(s.passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
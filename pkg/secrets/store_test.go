@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	store := NewFileStore(path, "passphrase")
+	assert.NilError(t, store.SetSecret("db_password", []byte("s3cr3t")))
+
+	content, err := NewFileStore(path, "passphrase").GetSecret(context.Background(), "db_password")
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+
+	raw, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(raw), "s3cr3t"))
+
+	_, err = NewFileStore(path, "wrong").GetSecret(context.Background(), "db_password")
+	assert.ErrorContains(t, err, "check passphrase")
+
+	_, err = store.GetSecret(context.Background(), "unknown")
+	assert.Assert(t, errors.Is(err, api.ErrNotFound))
+}
+
+func TestFileStorePassphraseCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	assert.NilError(t, NewFileStore(path, "passphrase").SetSecret("db_password", []byte("s3cr3t")))
+
+	err := NewFileStore(path, "mistyped").SetSecret("api_key", []byte("k3y"))
+	assert.Error(t, err, "passphrase doesn't match the one the stored secrets are encrypted with")
+	_, err = NewFileStore(path, "passphrase").GetSecret(context.Background(), "api_key")
+	assert.Assert(t, errors.Is(err, api.ErrNotFound))
+
+	err = NewFileStore(path, "").SetSecret("api_key", []byte("k3y"))
+	assert.Error(t, err, "a passphrase is required to encrypt secrets")
+	_, err = NewFileStore(path, "").GetSecret(context.Background(), "db_password")
+	assert.Error(t, err, "a passphrase is required to encrypt secrets")
+}
+
+func TestHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("secret helper test relies on a shell script")
+	}
+	program := filepath.Join(t.TempDir(), "compose-secrets-test")
+	script := `#!/bin/sh
+read name
+if [ "$name" = "db_password" ]; then
+  printf s3cr3t
+else
+  echo "secret not found" >&2
+  exit 1
+fi
+`
+	assert.NilError(t, ioutil.WriteFile(program, []byte(script), 0700))
+	helper := NewHelper(program)
+
+	content, err := helper.GetSecret(context.Background(), "db_password")
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+
+	_, err = helper.GetSecret(context.Background(), "unknown")
+	assert.Assert(t, errors.Is(err, api.ErrNotFound))
+}
@@ -0,0 +1,238 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"io"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapClient traces the engine API calls creating, changing or inspecting containers, images, networks and volumes.
+// Each call is a client span, child of the span found in the call context, so engine calls made by a compose command
+// are part of its trace. The engine connection is left untouched, as attach and exec hijack it
+func WrapClient(apiClient client.APIClient, provider trace.TracerProvider) client.APIClient {
+	return &tracedClient{
+		APIClient: apiClient,
+		tracer:    provider.Tracer(instrumentationName),
+	}
+}
+
+type tracedClient struct {
+	client.APIClient
+	tracer trace.Tracer
+}
+
+// start starts a span for an engine API call, the returned function ends it with the call error
+func (c *tracedClient) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, func(error)) {
+	ctx, span := c.tracer.Start(ctx, "engine."+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func containerAttribute(container string) attribute.KeyValue {
+	return attribute.String("docker.container", container)
+}
+
+func imageAttribute(image string) attribute.KeyValue {
+	return attribute.String("docker.image", image)
+}
+
+func networkAttribute(network string) attribute.KeyValue {
+	return attribute.String("docker.network", network)
+}
+
+func volumeAttribute(volume string) attribute.KeyValue {
+	return attribute.String("docker.volume", volume)
+}
+
+func (c *tracedClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	ctx, end := c.start(ctx, "ContainerCreate", containerAttribute(containerName))
+	created, err := c.APIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+	end(err)
+	return created, err
+}
+
+func (c *tracedClient) ContainerStart(ctx context.Context, container string, options moby.ContainerStartOptions) error {
+	ctx, end := c.start(ctx, "ContainerStart", containerAttribute(container))
+	err := c.APIClient.ContainerStart(ctx, container, options)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerStop(ctx context.Context, container string, timeout *time.Duration) error {
+	ctx, end := c.start(ctx, "ContainerStop", containerAttribute(container))
+	err := c.APIClient.ContainerStop(ctx, container, timeout)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerRestart(ctx context.Context, container string, timeout *time.Duration) error {
+	ctx, end := c.start(ctx, "ContainerRestart", containerAttribute(container))
+	err := c.APIClient.ContainerRestart(ctx, container, timeout)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerKill(ctx context.Context, container, signal string) error {
+	ctx, end := c.start(ctx, "ContainerKill", containerAttribute(container), attribute.String("docker.signal", signal))
+	err := c.APIClient.ContainerKill(ctx, container, signal)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerRemove(ctx context.Context, container string, options moby.ContainerRemoveOptions) error {
+	ctx, end := c.start(ctx, "ContainerRemove", containerAttribute(container))
+	err := c.APIClient.ContainerRemove(ctx, container, options)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerRename(ctx context.Context, container, newContainerName string) error {
+	ctx, end := c.start(ctx, "ContainerRename", containerAttribute(container))
+	err := c.APIClient.ContainerRename(ctx, container, newContainerName)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerPause(ctx context.Context, container string) error {
+	ctx, end := c.start(ctx, "ContainerPause", containerAttribute(container))
+	err := c.APIClient.ContainerPause(ctx, container)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerUnpause(ctx context.Context, container string) error {
+	ctx, end := c.start(ctx, "ContainerUnpause", containerAttribute(container))
+	err := c.APIClient.ContainerUnpause(ctx, container)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) ContainerInspect(ctx context.Context, container string) (moby.ContainerJSON, error) {
+	ctx, end := c.start(ctx, "ContainerInspect", containerAttribute(container))
+	inspected, err := c.APIClient.ContainerInspect(ctx, container)
+	end(err)
+	return inspected, err
+}
+
+func (c *tracedClient) ContainerList(ctx context.Context, options moby.ContainerListOptions) ([]moby.Container, error) {
+	ctx, end := c.start(ctx, "ContainerList")
+	containers, err := c.APIClient.ContainerList(ctx, options)
+	end(err)
+	return containers, err
+}
+
+func (c *tracedClient) ContainerExecCreate(ctx context.Context, container string, config moby.ExecConfig) (moby.IDResponse, error) {
+	ctx, end := c.start(ctx, "ContainerExecCreate", containerAttribute(container))
+	created, err := c.APIClient.ContainerExecCreate(ctx, container, config)
+	end(err)
+	return created, err
+}
+
+// ImagePull only covers the request, progress of the pull is traced by the service steps
+func (c *tracedClient) ImagePull(ctx context.Context, ref string, options moby.ImagePullOptions) (io.ReadCloser, error) {
+	ctx, end := c.start(ctx, "ImagePull", imageAttribute(ref))
+	stream, err := c.APIClient.ImagePull(ctx, ref, options)
+	end(err)
+	return stream, err
+}
+
+func (c *tracedClient) ImagePush(ctx context.Context, ref string, options moby.ImagePushOptions) (io.ReadCloser, error) {
+	ctx, end := c.start(ctx, "ImagePush", imageAttribute(ref))
+	stream, err := c.APIClient.ImagePush(ctx, ref, options)
+	end(err)
+	return stream, err
+}
+
+func (c *tracedClient) ImageBuild(ctx context.Context, buildContext io.Reader, options moby.ImageBuildOptions) (moby.ImageBuildResponse, error) {
+	ctx, end := c.start(ctx, "ImageBuild", attribute.StringSlice("docker.tags", options.Tags))
+	response, err := c.APIClient.ImageBuild(ctx, buildContext, options)
+	end(err)
+	return response, err
+}
+
+func (c *tracedClient) ImageInspectWithRaw(ctx context.Context, image string) (moby.ImageInspect, []byte, error) {
+	ctx, end := c.start(ctx, "ImageInspect", imageAttribute(image))
+	inspected, raw, err := c.APIClient.ImageInspectWithRaw(ctx, image)
+	end(err)
+	return inspected, raw, err
+}
+
+func (c *tracedClient) ImageRemove(ctx context.Context, image string, options moby.ImageRemoveOptions) ([]moby.ImageDeleteResponseItem, error) {
+	ctx, end := c.start(ctx, "ImageRemove", imageAttribute(image))
+	removed, err := c.APIClient.ImageRemove(ctx, image, options)
+	end(err)
+	return removed, err
+}
+
+func (c *tracedClient) NetworkCreate(ctx context.Context, name string, options moby.NetworkCreate) (moby.NetworkCreateResponse, error) {
+	ctx, end := c.start(ctx, "NetworkCreate", networkAttribute(name))
+	created, err := c.APIClient.NetworkCreate(ctx, name, options)
+	end(err)
+	return created, err
+}
+
+func (c *tracedClient) NetworkRemove(ctx context.Context, network string) error {
+	ctx, end := c.start(ctx, "NetworkRemove", networkAttribute(network))
+	err := c.APIClient.NetworkRemove(ctx, network)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) NetworkConnect(ctx context.Context, network, container string, config *network.EndpointSettings) error {
+	ctx, end := c.start(ctx, "NetworkConnect", networkAttribute(network), containerAttribute(container))
+	err := c.APIClient.NetworkConnect(ctx, network, container, config)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) NetworkDisconnect(ctx context.Context, network, container string, force bool) error {
+	ctx, end := c.start(ctx, "NetworkDisconnect", networkAttribute(network), containerAttribute(container))
+	err := c.APIClient.NetworkDisconnect(ctx, network, container, force)
+	end(err)
+	return err
+}
+
+func (c *tracedClient) VolumeCreate(ctx context.Context, options volume.VolumeCreateBody) (moby.Volume, error) {
+	ctx, end := c.start(ctx, "VolumeCreate", volumeAttribute(options.Name))
+	created, err := c.APIClient.VolumeCreate(ctx, options)
+	end(err)
+	return created, err
+}
+
+func (c *tracedClient) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	ctx, end := c.start(ctx, "VolumeRemove", volumeAttribute(volumeID))
+	err := c.APIClient.VolumeRemove(ctx, volumeID, force)
+	end(err)
+	return err
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/mocks"
+)
+
+func TestTracedClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	apiClient.EXPECT().ContainerStart(gomock.Any(), "test-db-1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, _ moby.ContainerStartOptions) error {
+			// the engine call gets the span context, to be propagated
+			assert.Assert(t, trace.SpanContextFromContext(ctx).IsValid())
+			return fmt.Errorf("port is already allocated")
+		})
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := NewProvider(sdktrace.WithSpanProcessor(recorder))
+	traced := WrapClient(apiClient, provider)
+	proxy := api.NewServiceProxy()
+	proxy.StartFn = func(ctx context.Context, project *types.Project, options api.StartOptions) error {
+		return traced.ContainerStart(ctx, "test-db-1", moby.ContainerStartOptions{})
+	}
+	service := WrapService(proxy, provider)
+
+	project := &types.Project{Name: "test", Services: types.Services{{Name: "db"}}}
+	err := service.Start(context.Background(), project, api.StartOptions{})
+	assert.Error(t, err, "port is already allocated")
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	start := spans["engine.ContainerStart"]
+	assert.Assert(t, start != nil)
+	assert.Equal(t, start.Parent().SpanID(), spans["Start"].SpanContext().SpanID())
+	assert.Equal(t, start.SpanKind(), trace.SpanKindClient)
+	assert.Equal(t, start.Status().Code, codes.Error)
+	assert.DeepEqual(t, start.Attributes(), []attribute.KeyValue{
+		attribute.String("docker.container", "test-db-1"),
+	}, cmpAttributes)
+}
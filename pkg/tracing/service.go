@@ -0,0 +1,362 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+const instrumentationName = "github.com/docker/compose/v2"
+
+// WrapService traces calls to service. Each call is a root span, with a child span for each service, container or
+// resource it reported progress about, itself having child spans for each step: pulling image (and its layers),
+// building, creating, starting, waiting for a healthcheck, and so on
+func WrapService(service api.Service, provider trace.TracerProvider) api.Service {
+	return &tracedService{
+		service: service,
+		tracer:  provider.Tracer(instrumentationName),
+	}
+}
+
+type tracedService struct {
+	service api.Service
+	tracer  trace.Tracer
+}
+
+var _ api.Service = &tracedService{}
+
+// call runs fn in a span named after the api.Service method, then creates child spans for the steps fn reported
+func (s *tracedService) call(ctx context.Context, method string, attributes []attribute.KeyValue, fn func(context.Context) error) error {
+	timings := progress.ContextTimings(ctx)
+	if timings == nil {
+		timings = progress.NewTimings()
+		ctx = progress.WithTimings(ctx, timings)
+	}
+	start := time.Now()
+	ctx, span := s.tracer.Start(ctx, method, trace.WithAttributes(attributes...))
+	err := fn(ctx)
+	s.stepSpans(ctx, timings, start)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	return err
+}
+
+func (s *tracedService) stepSpans(ctx context.Context, timings *progress.Timings, since time.Time) {
+	var ids []string
+	steps := map[string][]progress.Timing{}
+	subTasks := map[string][]progress.Timing{}
+	for _, timing := range timings.Timings() {
+		if timing.Start.Before(since) {
+			// recorded by a previous call
+			continue
+		}
+		if timing.ParentID != "" {
+			subTasks[timing.ParentID] = append(subTasks[timing.ParentID], timing)
+			continue
+		}
+		if _, ok := steps[timing.ID]; !ok {
+			ids = append(ids, timing.ID)
+		}
+		steps[timing.ID] = append(steps[timing.ID], timing)
+	}
+	for _, id := range ids {
+		start, end := steps[id][0].Start, steps[id][0].End
+		for _, timing := range append(append([]progress.Timing{}, steps[id]...), subTasks[id]...) {
+			if timing.Start.Before(start) {
+				start = timing.Start
+			}
+			if timing.End.After(end) {
+				end = timing.End
+			}
+		}
+		idCtx, span := s.tracer.Start(ctx, id, trace.WithTimestamp(start))
+		for _, timing := range steps[id] {
+			s.stepSpan(idCtx, timing)
+		}
+		for _, timing := range subTasks[id] {
+			s.stepSpan(idCtx, timing, attribute.String("compose.subtask", timing.ID))
+		}
+		span.End(trace.WithTimestamp(end))
+	}
+}
+
+func (s *tracedService) stepSpan(ctx context.Context, timing progress.Timing, attributes ...attribute.KeyValue) {
+	_, span := s.tracer.Start(ctx, timing.Step, trace.WithTimestamp(timing.Start), trace.WithAttributes(attributes...))
+	if timing.Failed {
+		span.SetStatus(codes.Error, timing.Step)
+	}
+	span.End(trace.WithTimestamp(timing.End))
+}
+
+func projectAttributes(project *types.Project) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("compose.project", project.Name),
+		attribute.StringSlice("compose.services", project.ServiceNames()),
+	}
+}
+
+func projectNameAttributes(projectName string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("compose.project", projectName),
+	}
+}
+
+func (s *tracedService) Build(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+	return s.call(ctx, "Build", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Build(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Push(ctx context.Context, project *types.Project, options api.PushOptions) error {
+	return s.call(ctx, "Push", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Push(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Pull(ctx context.Context, project *types.Project, options api.PullOptions) error {
+	return s.call(ctx, "Pull", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Pull(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Create(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+	return s.call(ctx, "Create", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Create(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Start(ctx context.Context, project *types.Project, options api.StartOptions) error {
+	return s.call(ctx, "Start", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Start(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
+	return s.call(ctx, "Restart", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Restart(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
+	return s.call(ctx, "Stop", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Stop(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Up(ctx context.Context, project *types.Project, options api.UpOptions) error {
+	return s.call(ctx, "Up", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Up(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
+	return s.call(ctx, "Down", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.Down(ctx, projectName, options)
+	})
+}
+
+func (s *tracedService) Logs(ctx context.Context, projectName string, consumer api.LogConsumer, options api.LogOptions) error {
+	return s.call(ctx, "Logs", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.Logs(ctx, projectName, consumer, options)
+	})
+}
+
+func (s *tracedService) Ps(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error) {
+	var result []api.ContainerSummary
+	err := s.call(ctx, "Ps", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.Ps(ctx, projectName, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) List(ctx context.Context, options api.ListOptions) ([]api.Stack, error) {
+	var result []api.Stack
+	err := s.call(ctx, "List", nil, func(ctx context.Context) error {
+		var err error
+		result, err = s.service.List(ctx, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) Convert(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
+	var result []byte
+	err := s.call(ctx, "Convert", projectAttributes(project), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.Convert(ctx, project, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) Kill(ctx context.Context, project *types.Project, options api.KillOptions) error {
+	return s.call(ctx, "Kill", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Kill(ctx, project, options)
+	})
+}
+
+func (s *tracedService) RunOneOffContainer(ctx context.Context, project *types.Project, options api.RunOptions) (int, error) {
+	var result int
+	err := s.call(ctx, "RunOneOffContainer", projectAttributes(project), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.RunOneOffContainer(ctx, project, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) Remove(ctx context.Context, project *types.Project, options api.RemoveOptions) error {
+	return s.call(ctx, "Remove", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Remove(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Exec(ctx context.Context, projectName string, options api.RunOptions) (int, error) {
+	var result int
+	err := s.call(ctx, "Exec", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.Exec(ctx, projectName, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) Copy(ctx context.Context, projectName string, options api.CopyOptions) error {
+	return s.call(ctx, "Copy", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.Copy(ctx, projectName, options)
+	})
+}
+
+func (s *tracedService) Pause(ctx context.Context, projectName string, options api.PauseOptions) error {
+	return s.call(ctx, "Pause", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.Pause(ctx, projectName, options)
+	})
+}
+
+func (s *tracedService) UnPause(ctx context.Context, projectName string, options api.PauseOptions) error {
+	return s.call(ctx, "UnPause", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.UnPause(ctx, projectName, options)
+	})
+}
+
+func (s *tracedService) Top(ctx context.Context, projectName string, services []string) ([]api.ContainerProcSummary, error) {
+	var result []api.ContainerProcSummary
+	err := s.call(ctx, "Top", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.Top(ctx, projectName, services)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) Events(ctx context.Context, projectName string, options api.EventsOptions) error {
+	return s.call(ctx, "Events", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.Events(ctx, projectName, options)
+	})
+}
+
+func (s *tracedService) Port(ctx context.Context, projectName string, service string, port int, options api.PortOptions) (string, int, error) {
+	var (
+		host      string
+		published int
+	)
+	err := s.call(ctx, "Port", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		host, published, err = s.service.Port(ctx, projectName, service, port, options)
+		return err
+	})
+	return host, published, err
+}
+
+func (s *tracedService) Images(ctx context.Context, projectName string, options api.ImagesOptions) ([]api.ImageSummary, error) {
+	var result []api.ImageSummary
+	err := s.call(ctx, "Images", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.Images(ctx, projectName, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) VolumesBackup(ctx context.Context, projectName string, w io.Writer, options api.VolumesBackupOptions) error {
+	return s.call(ctx, "VolumesBackup", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.VolumesBackup(ctx, projectName, w, options)
+	})
+}
+
+func (s *tracedService) VolumesRestore(ctx context.Context, projectName string, r io.Reader, options api.VolumesRestoreOptions) error {
+	return s.call(ctx, "VolumesRestore", projectNameAttributes(projectName), func(ctx context.Context) error {
+		return s.service.VolumesRestore(ctx, projectName, r, options)
+	})
+}
+
+func (s *tracedService) VolumesList(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error) {
+	var result []api.ResourceSummary
+	err := s.call(ctx, "VolumesList", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.VolumesList(ctx, projectName, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) VolumesPrune(ctx context.Context, project *types.Project, options api.VolumesPruneOptions) error {
+	return s.call(ctx, "VolumesPrune", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.VolumesPrune(ctx, project, options)
+	})
+}
+
+func (s *tracedService) NetworksList(ctx context.Context, projectName string, options api.ResourcesListOptions) ([]api.ResourceSummary, error) {
+	var result []api.ResourceSummary
+	err := s.call(ctx, "NetworksList", projectNameAttributes(projectName), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.NetworksList(ctx, projectName, options)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracedService) Supervise(ctx context.Context, project *types.Project, options api.SuperviseOptions) error {
+	return s.call(ctx, "Supervise", projectAttributes(project), func(ctx context.Context) error {
+		return s.service.Supervise(ctx, project, options)
+	})
+}
+
+func (s *tracedService) Lint(ctx context.Context, project *types.Project, options api.LintOptions) ([]api.LintFinding, error) {
+	var result []api.LintFinding
+	err := s.call(ctx, "Lint", projectAttributes(project), func(ctx context.Context) error {
+		var err error
+		result, err = s.service.Lint(ctx, project, options)
+		return err
+	})
+	return result, err
+}
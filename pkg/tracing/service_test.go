@@ -0,0 +1,120 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	gocmp "github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+)
+
+func upWithProgress(ctx context.Context, project *types.Project, options api.UpOptions) error {
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{ID: "db", Status: progress.Working, Text: "Pulling"})
+	w.Event(progress.Event{ID: "a1b2", ParentID: "db", Status: progress.Working, Text: "Downloading"})
+	w.Event(progress.Event{ID: "a1b2", ParentID: "db", Status: progress.Done, Text: "Pull complete"})
+	w.Event(progress.Event{ID: "db", Status: progress.Done, Text: "Pulled"})
+	w.Event(progress.CreatingEvent("Container test-db-1"))
+	w.Event(progress.CreatedEvent("Container test-db-1"))
+	w.Event(progress.StartingEvent("Container test-db-1"))
+	w.Event(progress.ErrorMessageEvent("Container test-db-1", "Error response from daemon"))
+	return fmt.Errorf("container test-db-1 failed to start")
+}
+
+func TestTracedService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := NewProvider(sdktrace.WithSpanProcessor(recorder))
+	proxy := api.NewServiceProxy()
+	proxy.UpFn = upWithProgress
+	service := WrapService(proxy, provider)
+
+	project := &types.Project{Name: "test", Services: types.Services{{Name: "db"}}}
+	err := service.Up(context.Background(), project, api.UpOptions{})
+	assert.Error(t, err, "container test-db-1 failed to start")
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	assert.Equal(t, len(recorder.Ended()), 7)
+
+	up := spans["Up"]
+	assert.Assert(t, !up.Parent().IsValid())
+	assert.Equal(t, up.Status().Code, codes.Error)
+	assert.DeepEqual(t, up.Attributes(), []attribute.KeyValue{
+		attribute.String("compose.project", "test"),
+		attribute.StringSlice("compose.services", []string{"db"}),
+	}, cmpAttributes)
+
+	db := spans["db"]
+	assert.Equal(t, db.Parent().SpanID(), up.SpanContext().SpanID())
+	assert.Equal(t, spans["Pulling"].Parent().SpanID(), db.SpanContext().SpanID())
+	assert.Equal(t, spans["Downloading"].Parent().SpanID(), db.SpanContext().SpanID())
+	assert.DeepEqual(t, spans["Downloading"].Attributes(), []attribute.KeyValue{
+		attribute.String("compose.subtask", "a1b2"),
+	}, cmpAttributes)
+
+	container := spans["Container test-db-1"]
+	assert.Equal(t, container.Parent().SpanID(), up.SpanContext().SpanID())
+	assert.Equal(t, spans["Creating"].Parent().SpanID(), container.SpanContext().SpanID())
+	assert.Equal(t, spans["Starting"].Parent().SpanID(), container.SpanContext().SpanID())
+	assert.Equal(t, spans["Starting"].Status().Code, codes.Error)
+}
+
+func TestTracesFileExporter(t *testing.T) {
+	out := &bytes.Buffer{}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(out))
+	assert.NilError(t, err)
+	proxy := api.NewServiceProxy()
+	proxy.DownFn = func(ctx context.Context, projectName string, options api.DownOptions) error {
+		return nil
+	}
+	service := WrapService(proxy, NewProvider(sdktrace.WithSyncer(exporter)))
+
+	assert.NilError(t, service.Down(context.Background(), "test", api.DownOptions{}))
+	var span struct {
+		Name       string
+		Attributes []struct {
+			Key   string
+			Value struct {
+				Value interface{}
+			}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &span))
+	assert.Equal(t, span.Name, "Down")
+	assert.Equal(t, span.Attributes[0].Key, "compose.project")
+	assert.Equal(t, span.Attributes[0].Value.Value, "test")
+}
+
+var cmpAttributes = gocmp.Comparer(func(a, b attribute.KeyValue) bool {
+	return a.Key == b.Key && a.Value.Emit() == b.Value.Emit()
+})
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/docker/compose/v2/internal"
+)
+
+const (
+	// TracesFileEnvVar sets a file traces are appended to, as a stream of JSON spans
+	TracesFileEnvVar = "COMPOSE_TRACES_FILE"
+	// OTLPEndpointEnvVar sets the OTLP/HTTP collector endpoint traces are exported to
+	OTLPEndpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// OTLPTracesEndpointEnvVar sets the OTLP/HTTP collector endpoint for traces only
+	OTLPTracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+
+	serviceName = "docker-compose"
+)
+
+// InitProvider sets up the global tracer provider with the exporters configured by environment, and returns it, or nil
+// when none is configured. The provider must be shut down before the process exits, to flush batched spans
+func InitProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	var options []sdktrace.TracerProviderOption
+	if path, ok := os.LookupEnv(TracesFileEnvVar); ok && path != "" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot open %s", TracesFileEnvVar)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			return nil, err
+		}
+		// spans are written as they end, so they're not lost when the command exits
+		options = append(options, sdktrace.WithSyncer(exporter))
+	}
+	if os.Getenv(OTLPEndpointEnvVar) != "" || os.Getenv(OTLPTracesEndpointEnvVar) != "" {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	if len(options) == 0 {
+		return nil, nil
+	}
+	provider := NewProvider(options...)
+	otel.SetTracerProvider(provider)
+	return provider, nil
+}
+
+// NewProvider returns a tracer provider identifying spans as emitted by compose
+func NewProvider(options ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(internal.Version),
+	)
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, options...)...)
+}
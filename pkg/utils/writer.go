@@ -60,3 +60,21 @@ func (s *splitWriter) Close() error {
 	s.consumer(string(b))
 	return nil
 }
+
+// GetCRLFWriter creates a io.Writer terminating lines with a carriage return, as required by a terminal in raw mode
+func GetCRLFWriter(out io.Writer) io.Writer {
+	return &crlfWriter{out: out}
+}
+
+type crlfWriter struct {
+	out io.Writer
+}
+
+// Write implements io.Writer. prepends a carriage return to each line feed
+func (w *crlfWriter) Write(b []byte) (int, error) {
+	_, err := w.out.Write(bytes.ReplaceAll(b, []byte{'\n'}, []byte{'\r', '\n'}))
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -17,6 +17,7 @@
 package utils
 
 import (
+	"bytes"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -37,3 +38,12 @@ func TestSplitWriter(t *testing.T) {
 	assert.DeepEqual(t, lines, []string{"hello", "world!"})
 
 }
+
+func TestCRLFWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := GetCRLFWriter(&b)
+	n, err := w.Write([]byte("hello\nworld!\n"))
+	assert.NilError(t, err)
+	assert.Equal(t, n, 13)
+	assert.Equal(t, b.String(), "hello\r\nworld!\r\n")
+}